@@ -0,0 +1,61 @@
+package cua
+
+import (
+	"context"
+	"time"
+)
+
+// BatchResult summarizes a RunAll batch.
+type BatchResult struct {
+	// Results holds one result per task attempted, in task order.
+	Results []*Result `json:"results"`
+	// SuccessCount is the number of tasks that completed without error.
+	SuccessCount int `json:"success_count"`
+	// DurationMs is the sum of the tasks' execution times in milliseconds.
+	DurationMs int64 `json:"duration_ms"`
+	// ToolCalls is the total number of tool calls (steps) across the tasks.
+	ToolCalls int `json:"tool_calls"`
+	// Usage is the batch's aggregated usage; TotalRuns is the number of tasks run.
+	Usage UsageStats `json:"usage"`
+}
+
+// add aggregates one task's result into the batch.
+func (b *BatchResult) add(result *Result) {
+	b.Results = append(b.Results, result)
+	if result.Success {
+		b.SuccessCount++
+	}
+	b.DurationMs += result.DurationMs
+	b.ToolCalls += result.ToolCalls
+	b.Usage.Add(result.Usage, result.LLMCalls, result.ToolCalls, result.DurationMs)
+}
+
+// RunAll runs tasks one after another and returns their aggregated results.
+// A failed task does not stop the batch; check each Result (or SuccessCount).
+// If ctx is done, the remaining tasks are skipped and ctx's error is returned
+// along with the results so far.
+func (c *CUA) RunAll(ctx context.Context, tasks ...string) (*BatchResult, error) {
+	batch := &BatchResult{}
+	for _, task := range tasks {
+		if err := ctx.Err(); err != nil {
+			return batch, err
+		}
+
+		start := time.Now()
+		_, result, err := c.runDetailed(ctx, task)
+		if result == nil {
+			// The task never started; record it so Results lines up with tasks
+			result = &Result{
+				Task:      task,
+				Error:     err.Error(),
+				Reason:    resultReason(err),
+				Provider:  c.config.Provider,
+				Model:     c.modelName(),
+				OrgID:     c.config.OrgID,
+				StartedAt: start,
+			}
+		}
+		batch.add(result)
+	}
+	return batch, nil
+}
@@ -0,0 +1,100 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestBatchResultAdd(t *testing.T) {
+	results := []*Result{
+		{Task: "a", Success: true, DurationMs: 100, LLMCalls: 2, ToolCalls: 3,
+			Usage: &TokenUsage{InputTokens: 10, OutputTokens: 5, TotalTokens: 15}},
+		{Task: "b", Success: false, DurationMs: 250, LLMCalls: 1, ToolCalls: 4,
+			Usage: &TokenUsage{InputTokens: 20, OutputTokens: 7, TotalTokens: 27, ReasoningTokens: 3}},
+		{Task: "c", Success: true, DurationMs: 50, ToolCalls: 1}, // No usage reported
+		{Task: "d", Success: false},                              // Never started
+	}
+
+	batch := &BatchResult{}
+	for _, r := range results {
+		batch.add(r)
+	}
+
+	if len(batch.Results) != 4 || batch.Results[1].Task != "b" {
+		t.Errorf("Results = %v, want all four in order", batch.Results)
+	}
+	checks := []struct {
+		name      string
+		got, want int64
+	}{
+		{"SuccessCount", int64(batch.SuccessCount), 2},
+		{"DurationMs", batch.DurationMs, 400},
+		{"ToolCalls", int64(batch.ToolCalls), 8},
+		{"Usage.TotalRuns", int64(batch.Usage.TotalRuns), 4},
+		{"Usage.TotalLLMCalls", int64(batch.Usage.TotalLLMCalls), 3},
+		{"Usage.TotalToolCalls", int64(batch.Usage.TotalToolCalls), 8},
+		{"Usage.TotalTimeMs", batch.Usage.TotalTimeMs, 400},
+		{"Usage.TotalInputTokens", int64(batch.Usage.TotalInputTokens), 30},
+		{"Usage.TotalOutputTokens", int64(batch.Usage.TotalOutputTokens), 12},
+		{"Usage.TotalTokens", int64(batch.Usage.TotalTokens), 42},
+		{"Usage.TotalReasoningTokens", int64(batch.Usage.TotalReasoningTokens), 3},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %d, want %d", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestRunAllCancelled(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	batch, err := c.RunAll(ctx, "a", "b")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(batch.Results) != 0 {
+		t.Errorf("ran %d tasks after cancellation, want 0", len(batch.Results))
+	}
+}
+
+func TestRunAllRecordsTasksThatNeverStarted(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	// Another run holds the queue for longer than the batch may wait
+	c.queue = &runQueue{busy: true}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	batch, err := c.RunAll(ctx, "first", "second")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("err = %v, want context.DeadlineExceeded", err)
+	}
+	if len(batch.Results) != 1 {
+		t.Fatalf("got %d results, want 1 for the task that waited", len(batch.Results))
+	}
+	r := batch.Results[0]
+	if r.Task != "first" || r.Success || r.Reason != ReasonTimeout || r.Error == "" {
+		t.Errorf("result = %+v, want a failed timeout for the first task", r)
+	}
+	if batch.SuccessCount != 0 || batch.Usage.TotalRuns != 1 {
+		t.Errorf("SuccessCount = %d, TotalRuns = %d; want 0 and 1", batch.SuccessCount, batch.Usage.TotalRuns)
+	}
+}
+
+func TestRunAllRecordsRejectedPlans(t *testing.T) {
+	cfg := defaultConfig()
+	WithRequirePlanApproval(func(string) bool { return false })(cfg)
+	c := newPlanCUA(cfg, &fakeLLM{response: testPlan})
+
+	batch, err := c.RunAll(context.Background(), "write a note")
+	if err != nil {
+		t.Fatalf("RunAll: %v", err)
+	}
+	if len(batch.Results) != 1 || batch.Results[0].Reason != ReasonPlanRejected {
+		t.Errorf("results = %+v, want one with reason %q", batch.Results, ReasonPlanRejected)
+	}
+}
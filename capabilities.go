@@ -0,0 +1,97 @@
+package cua
+
+import (
+	"maps"
+
+	"github.com/anxuanzi/cua/internal/platform"
+)
+
+// Capabilities summarizes how an agent is configured: its model, limits,
+// tools, safety settings, and host platform.
+type Capabilities struct {
+	Provider LLMProvider `json:"provider"`
+	Model    string      `json:"model"`
+	// FallbackModels are tried in order when a run fails (see WithModelFallback).
+	FallbackModels []string `json:"fallback_models,omitempty"`
+
+	// Vision reports whether screenshots are sent to the model.
+	Vision bool `json:"vision"`
+	// Reasoning reports whether extended thinking is enabled.
+	Reasoning bool `json:"reasoning"`
+
+	// Limits
+	MaxIterations  int `json:"max_iterations"`
+	MaxActions     int `json:"max_actions,omitempty"`
+	TimeoutSeconds int `json:"timeout_seconds"`
+	TokenLimit     int `json:"token_limit,omitempty"`
+	// Queueing reports whether concurrent runs execute one at a time.
+	Queueing bool `json:"queueing"`
+
+	// Tools lists the names of the tools available to the model.
+	Tools []string `json:"tools"`
+
+	// Safety
+	BlockedKeyCombos  []string       `json:"blocked_key_combos,omitempty"`
+	ActionFilter      bool           `json:"action_filter"`
+	ToolConfirmation  bool           `json:"tool_confirmation"`
+	ActionRateLimit   int            `json:"action_rate_limit,omitempty"`
+	ActionRateLimits  map[string]int `json:"action_rate_limits,omitempty"`
+	OperatingHours    string         `json:"operating_hours,omitempty"` // "HH:MM-HH:MM"
+	BlockOnLockScreen bool           `json:"block_on_lock_screen"`
+
+	// Typing
+	KeyboardLayout string `json:"keyboard_layout,omitempty"`
+	TypeFileRoot   string `json:"type_file_root,omitempty"`
+	PasteThreshold int    `json:"paste_threshold,omitempty"`
+
+	// Platform
+	OS        string `json:"os"`
+	OSVersion string `json:"os_version,omitempty"`
+	Arch      string `json:"arch"`
+}
+
+// Capabilities returns a report of the agent's configuration.
+// It has no side effects and can be called before running any task.
+func (c *CUA) Capabilities() Capabilities {
+	toolNames := make([]string, len(c.tools))
+	for i, t := range c.tools {
+		toolNames[i] = t.Name()
+	}
+
+	var rateLimits map[string]int
+	if len(c.config.ActionRateLimits) > 0 {
+		rateLimits = maps.Clone(c.config.ActionRateLimits)
+	}
+	var hours string
+	if h := c.config.OperatingHours; h != nil {
+		hours = formatClock(h.Start) + "-" + formatClock(h.End)
+	}
+
+	info := platform.Current()
+	return Capabilities{
+		Provider:          c.config.Provider,
+		Model:             c.modelName(),
+		FallbackModels:    append([]string(nil), c.config.FallbackModels...),
+		Vision:            c.config.Vision,
+		Reasoning:         c.config.EnableReasoning,
+		MaxIterations:     c.config.MaxIterations,
+		MaxActions:        c.config.MaxActions,
+		TimeoutSeconds:    c.config.Timeout,
+		TokenLimit:        c.config.TokenLimit,
+		Queueing:          c.config.Queueing,
+		Tools:             toolNames,
+		BlockedKeyCombos:  append([]string(nil), c.config.BlockedKeyCombos...),
+		ActionFilter:      c.config.ActionFilter != nil,
+		ToolConfirmation:  c.config.ToolConfirmation != nil,
+		ActionRateLimit:   c.config.ActionRateLimit,
+		ActionRateLimits:  rateLimits,
+		OperatingHours:    hours,
+		BlockOnLockScreen: c.config.BlockOnLockScreen,
+		KeyboardLayout:    c.config.KeyboardLayout,
+		TypeFileRoot:      c.config.TypeFileRoot,
+		PasteThreshold:    c.config.PasteThreshold,
+		OS:                info.DisplayName,
+		OSVersion:         info.Version,
+		Arch:              info.Arch,
+	}
+}
@@ -0,0 +1,117 @@
+package cua
+
+import (
+	"io"
+	"reflect"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCapabilities(t *testing.T) {
+	allow := func(string, map[string]any) (FilterDecision, map[string]any) { return FilterAllow, nil }
+
+	tests := []struct {
+		name string
+		opts []Option
+		want Capabilities // Tools and platform fields are checked separately
+	}{
+		{
+			name: "defaults",
+			want: Capabilities{
+				Provider:       ProviderAnthropic,
+				Model:          defaultModels[ProviderAnthropic],
+				Vision:         true,
+				Reasoning:      defaultConfig().EnableReasoning,
+				MaxIterations:  defaultConfig().MaxIterations,
+				TimeoutSeconds: defaultConfig().Timeout,
+			},
+		},
+		{
+			name: "configured",
+			opts: []Option{
+				WithProvider(ProviderAnthropic),
+				WithModel("claude-test"),
+				WithModelFallback("claude-big"),
+				WithReasoning(true),
+				WithMaxIterations(7),
+				WithTimeout(90),
+				WithTokenLimit(5000),
+				WithDangerousKeyBlocking(nil, "cmd+q"),
+				WithActionFilter(allow),
+				WithMaxActions(30),
+				WithQueueing(true),
+				WithToolConfirmationPrompt(strings.NewReader(""), io.Discard),
+				WithActionRateLimit(20),
+				WithActionRateLimits(map[string]int{"screen_capture": 60}),
+				WithOperatingHours(9*time.Hour, 17*time.Hour+30*time.Minute),
+				WithBlockOnLockScreen(true),
+				WithKeyboardLayout("de"),
+				WithTypeFileRoot("/srv/forms"),
+				WithPasteThreshold(500),
+			},
+			want: Capabilities{
+				Provider:          ProviderAnthropic,
+				Model:             "claude-test",
+				FallbackModels:    []string{"claude-big"},
+				Vision:            true,
+				Reasoning:         true,
+				MaxIterations:     7,
+				TimeoutSeconds:    90,
+				TokenLimit:        5000,
+				BlockedKeyCombos:  []string{"cmd+q"},
+				ActionFilter:      true,
+				MaxActions:        30,
+				Queueing:          true,
+				ToolConfirmation:  true,
+				ActionRateLimit:   20,
+				ActionRateLimits:  map[string]int{"screen_capture": 60},
+				OperatingHours:    "09:00-17:30",
+				BlockOnLockScreen: true,
+				KeyboardLayout:    "de",
+				TypeFileRoot:      "/srv/forms",
+				PasteThreshold:    500,
+			},
+		},
+		{
+			name: "local without vision",
+			opts: []Option{WithProvider(ProviderLocal), WithModel("llava"), WithVisionDisabled()},
+			want: Capabilities{
+				Provider:       ProviderLocal,
+				Model:          "llava",
+				Reasoning:      defaultConfig().EnableReasoning,
+				MaxIterations:  defaultConfig().MaxIterations,
+				TimeoutSeconds: defaultConfig().Timeout,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			for _, opt := range tt.opts {
+				opt(cfg)
+			}
+			c := newTestCUA(cfg)
+			c.tools = c.wrapTools(createTools(cfg, newToolState(cfg)))
+
+			got := c.Capabilities()
+
+			if got.Arch != runtime.GOARCH || got.OS == "" {
+				t.Errorf("platform = %s/%s, want this host", got.OS, got.Arch)
+			}
+			if slices.Contains(got.Tools, "screen_capture") != cfg.Vision {
+				t.Errorf("tools %v: screen_capture listed = %v, want %v", got.Tools, !cfg.Vision, cfg.Vision)
+			}
+			if len(got.Tools) != len(c.tools) {
+				t.Errorf("got %d tools, want %d", len(got.Tools), len(c.tools))
+			}
+
+			got.Tools, got.OS, got.OSVersion, got.Arch = nil, "", "", ""
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Capabilities =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,49 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// controlServerShutdownTimeout bounds how long Close waits for in-flight
+// control server requests.
+const controlServerShutdownTimeout = 5 * time.Second
+
+// ErrClosed is returned by runs started, or still queued, after Close.
+var ErrClosed = errors.New("agent closed")
+
+// Close shuts the agent down. It cancels in-flight runs and waits for them to
+// finish, rejects queued and new runs with ErrClosed, shuts down the control
+// server (see WithControlServer), and then closes the result store if it
+// implements io.Closer (see WithResultStore), so cancelled runs' results are
+// saved first.
+//
+// Close is safe to call more than once; later calls return the first call's
+// error.
+func (c *CUA) Close() error {
+	c.closeOnce.Do(func() {
+		if c.queue != nil {
+			c.queue.close()
+		}
+		c.control.close()
+
+		var errs []error
+		if c.controlServer != nil {
+			ctx, cancel := context.WithTimeout(context.Background(), controlServerShutdownTimeout)
+			if err := c.controlServer.Shutdown(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("failed to shut down control server: %w", err))
+			}
+			cancel()
+		}
+		if closer, ok := c.config.ResultStore.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, fmt.Errorf("failed to close result store: %w", err))
+			}
+		}
+		c.closeErr = errors.Join(errs...)
+	})
+	return c.closeErr
+}
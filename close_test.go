@@ -0,0 +1,148 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// closingStore is a ResultStore that counts Close calls.
+type closingStore struct {
+	spyStore
+	closes int
+	err    error
+}
+
+func (s *closingStore) Close() error {
+	s.closes++
+	return s.err
+}
+
+func TestClose(t *testing.T) {
+	store := &closingStore{err: errors.New("disk full")}
+	cfg := defaultConfig()
+	WithResultStore(store)(cfg)
+	WithControlServer("127.0.0.1:0", testToken)(cfg)
+	c := newTestCUA(cfg)
+	if err := c.startControlServer(); err != nil {
+		t.Fatalf("startControlServer: %v", err)
+	}
+	runCtx, done, err := c.control.track(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		<-runCtx.Done()
+		done()
+	}()
+
+	err = c.Close()
+	if !errors.Is(err, store.err) {
+		t.Errorf("Close = %v, want the store's error", err)
+	}
+	if runCtx.Err() == nil {
+		t.Error("running task not stopped")
+	}
+	if store.closes != 1 {
+		t.Errorf("store closed %d times, want 1", store.closes)
+	}
+
+	// A shut-down server refuses to serve again
+	listener, lerr := net.Listen("tcp", "127.0.0.1:0")
+	if lerr != nil {
+		t.Fatal(lerr)
+	}
+	defer listener.Close()
+	if serveErr := c.controlServer.Serve(listener); !errors.Is(serveErr, http.ErrServerClosed) {
+		t.Errorf("control server still serving after Close: %v", serveErr)
+	}
+
+	if again := c.Close(); again != err {
+		t.Errorf("second Close = %v, want the first call's error %v", again, err)
+	}
+	if store.closes != 1 {
+		t.Errorf("store closed %d times after a second Close, want 1", store.closes)
+	}
+}
+
+func TestCloseWithoutResources(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	if err := c.Close(); err != nil {
+		t.Errorf("Close = %v, want nil", err)
+	}
+	if err := c.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+}
+
+func TestCloseWaitsForRunsBeforeClosingStore(t *testing.T) {
+	store := &closingStore{}
+	cfg := defaultConfig()
+	WithResultStore(store)(cfg)
+	c := newTestCUA(cfg)
+
+	runCtx, done, err := c.control.track(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	saved := make(chan struct{})
+	go func() {
+		<-runCtx.Done()
+		// A cancelled run still records its result before finishing
+		time.Sleep(20 * time.Millisecond)
+		c.recordResult(runCtx, &Result{Task: "open notes", Reason: ReasonCancelled})
+		if store.closes != 0 {
+			t.Error("result store closed before the cancelled run finished")
+		}
+		done()
+		close(saved)
+	}()
+
+	if err := c.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	<-saved
+	if len(store.results) != 1 || store.closes != 1 {
+		t.Errorf("store saved %d results and was closed %d times, want 1 and 1", len(store.results), store.closes)
+	}
+}
+
+func TestRunAfterClose(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	c.queue = &runQueue{}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Run(context.Background(), "open notes"); !errors.Is(err, ErrClosed) {
+		t.Errorf("Run after Close = %v, want ErrClosed", err)
+	}
+	if _, err := c.RunStream(context.Background(), "open notes"); !errors.Is(err, ErrClosed) {
+		t.Errorf("RunStream after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestCloseRejectsQueuedRuns(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	c.queue = &runQueue{}
+	if err := c.queue.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	queued := make(chan error, 1)
+	go func() {
+		_, err := c.Run(context.Background(), "open notes")
+		queued <- err
+	}()
+	waitForWaiters(t, c.queue, 1)
+
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-queued; !errors.Is(err, ErrClosed) {
+		t.Errorf("queued Run = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,125 @@
+package cua
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// ToolConfirmFunc approves a tool call like an ActionFilter, but may block
+// waiting for a user. It must deny the call promptly once ctx is done, which
+// happens when the run is cancelled, stopped, or the agent is closed.
+type ToolConfirmFunc func(ctx context.Context, action string, args map[string]any) (FilterDecision, map[string]any)
+
+// confirmPrompt asks a user in a terminal to approve each action.
+type confirmPrompt struct {
+	in     *bufio.Reader
+	out    io.Writer
+	turn   chan struct{}   // Holds a token while a call is being prompted for
+	always map[string]bool // Actions approved for the rest of the session; guarded by turn
+
+	readOnce sync.Once
+	lines    chan string // Lines read from in; closed at end of input
+}
+
+// NewConfirmationPrompt returns a ToolConfirmFunc that asks the user, on out,
+// to approve each action tool call and reads the answer from in:
+//
+//	y / yes     - run this call
+//	n / no      - deny this call (the model receives an error observation)
+//	a / always  - run this call and every later call of the same tool
+//
+// Observation tools (screen_capture, screen_info, app_list) are not prompted
+// for. An unrecognised answer asks again; end of input denies. Calls are
+// prompted one at a time, so it is safe to share between runs. A call whose
+// context is done while it is prompted for, or waiting its turn, is denied.
+func NewConfirmationPrompt(in io.Reader, out io.Writer) ToolConfirmFunc {
+	p := &confirmPrompt{
+		in:     bufio.NewReader(in),
+		out:    out,
+		turn:   make(chan struct{}, 1),
+		always: make(map[string]bool),
+		lines:  make(chan string),
+	}
+	return p.confirm
+}
+
+func (p *confirmPrompt) confirm(ctx context.Context, action string, args map[string]any) (FilterDecision, map[string]any) {
+	if observationTools[action] {
+		return FilterAllow, nil
+	}
+
+	// Wait for any other call's prompt to be answered first
+	select {
+	case p.turn <- struct{}{}:
+		defer func() { <-p.turn }()
+	case <-ctx.Done():
+		return FilterDeny, nil
+	}
+	if p.always[action] {
+		return FilterAllow, nil
+	}
+
+	// Read in the background so a cancelled run isn't stuck on a blocking read
+	p.readOnce.Do(func() { go p.readLines() })
+
+	argsJSON, _ := json.Marshal(args)
+	for {
+		fmt.Fprintf(p.out, "Allow %s %s? [y]es/[n]o/[a]lways: ", action, argsJSON)
+		var line string
+		var ok bool
+		select {
+		case line, ok = <-p.lines:
+		case <-ctx.Done():
+			fmt.Fprintln(p.out, "cancelled")
+			return FilterDeny, nil
+		}
+		if !ok {
+			// No more input; fail closed
+			fmt.Fprintln(p.out)
+			return FilterDeny, nil
+		}
+
+		decision, always, recognised := parseConfirmation(line)
+		if !recognised {
+			fmt.Fprintln(p.out, "Please answer y, n, or a.")
+			continue
+		}
+		if always {
+			p.always[action] = true
+		}
+		return decision, nil
+	}
+}
+
+// readLines sends each line of input to p.lines and closes it at end of input.
+func (p *confirmPrompt) readLines() {
+	defer close(p.lines)
+	for {
+		line, err := p.in.ReadString('\n')
+		if line != "" {
+			p.lines <- line
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// parseConfirmation parses a prompt answer. ok is false if the answer is not
+// recognised.
+func parseConfirmation(answer string) (decision FilterDecision, always, ok bool) {
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return FilterAllow, false, true
+	case "n", "no":
+		return FilterDeny, false, true
+	case "a", "always":
+		return FilterAllow, true, true
+	}
+	return FilterDeny, false, false
+}
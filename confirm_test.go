@@ -0,0 +1,192 @@
+package cua
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestParseConfirmation(t *testing.T) {
+	tests := []struct {
+		answer       string
+		wantDecision FilterDecision
+		wantAlways   bool
+		wantOK       bool
+	}{
+		{"y\n", FilterAllow, false, true},
+		{"YES\n", FilterAllow, false, true},
+		{"  n  \n", FilterDeny, false, true},
+		{"no\n", FilterDeny, false, true},
+		{"a\n", FilterAllow, true, true},
+		{"Always\n", FilterAllow, true, true},
+		{"maybe\n", FilterDeny, false, false},
+		{"\n", FilterDeny, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(strings.TrimSpace(tt.answer), func(t *testing.T) {
+			decision, always, ok := parseConfirmation(tt.answer)
+			if decision != tt.wantDecision || always != tt.wantAlways || ok != tt.wantOK {
+				t.Errorf("parseConfirmation(%q) = (%v, %v, %v), want (%v, %v, %v)",
+					tt.answer, decision, always, ok, tt.wantDecision, tt.wantAlways, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestConfirmationPrompt(t *testing.T) {
+	type call struct {
+		action string
+		want   FilterDecision
+	}
+	tests := []struct {
+		name        string
+		input       string
+		calls       []call
+		wantPrompts int
+	}{
+		{
+			name:        "yes and no",
+			input:       "y\nn\n",
+			calls:       []call{{"mouse_click", FilterAllow}, {"mouse_click", FilterDeny}},
+			wantPrompts: 2,
+		},
+		{
+			name:  "always is remembered per action",
+			input: "a\nn\n",
+			calls: []call{
+				{"keyboard_type", FilterAllow},
+				{"keyboard_type", FilterAllow},
+				{"mouse_click", FilterDeny},
+				{"keyboard_type", FilterAllow},
+			},
+			wantPrompts: 2,
+		},
+		{
+			name:        "unrecognised answers ask again",
+			input:       "what\n\ny\n",
+			calls:       []call{{"mouse_click", FilterAllow}},
+			wantPrompts: 3,
+		},
+		{
+			name:        "end of input denies",
+			input:       "",
+			calls:       []call{{"mouse_click", FilterDeny}},
+			wantPrompts: 1,
+		},
+		{
+			name:        "observations are not prompted",
+			input:       "",
+			calls:       []call{{"screen_capture", FilterAllow}, {"screen_info", FilterAllow}, {"app_list", FilterAllow}},
+			wantPrompts: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			filter := NewConfirmationPrompt(strings.NewReader(tt.input), &out)
+
+			for i, c := range tt.calls {
+				decision, _ := filter(context.Background(), c.action, map[string]any{"x": 1})
+				if decision != c.want {
+					t.Errorf("call %d (%s) = %v, want %v", i, c.action, decision, c.want)
+				}
+			}
+			if got := strings.Count(out.String(), "? [y]es/[n]o/[a]lways: "); got != tt.wantPrompts {
+				t.Errorf("prompted %d times, want %d; output:\n%s", got, tt.wantPrompts, out.String())
+			}
+		})
+	}
+}
+
+func TestConfirmationPromptShowsArguments(t *testing.T) {
+	var out bytes.Buffer
+	filter := NewConfirmationPrompt(strings.NewReader("y\n"), &out)
+	filter(context.Background(), "keyboard_type", map[string]any{"text": "hello"})
+
+	if want := `Allow keyboard_type {"text":"hello"}?`; !strings.Contains(out.String(), want) {
+		t.Errorf("prompt %q does not contain %q", out.String(), want)
+	}
+}
+
+func TestExecuteToolConfirmation(t *testing.T) {
+	var out bytes.Buffer
+	cfg := defaultConfig()
+	WithToolConfirmationPrompt(strings.NewReader("n\ny\n"), &out)(cfg)
+	c := newTestCUA(cfg)
+	tool := &fakeTool{name: "mouse_click"}
+
+	result, err := c.executeTool(context.Background(), tool, "{}")
+	if err != nil {
+		t.Fatalf("executeTool: %v", err)
+	}
+	if !strings.Contains(result, "action declined by the user: mouse_click") {
+		t.Errorf("result = %s, want a declined observation", result)
+	}
+	if n := tool.calls.Load(); n != 0 {
+		t.Fatalf("declined tool ran %d times", n)
+	}
+
+	if _, err := c.executeTool(context.Background(), tool, "{}"); err != nil {
+		t.Fatalf("executeTool: %v", err)
+	}
+	if n := tool.calls.Load(); n != 1 {
+		t.Errorf("approved tool ran %d times, want 1", n)
+	}
+}
+
+func TestConfirmationPromptCancel(t *testing.T) {
+	in, _ := io.Pipe() // Never answered
+	var out syncBuffer
+	confirm := NewConfirmationPrompt(in, &out)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	// One call is prompted for while another waits its turn
+	decisions := make(chan FilterDecision, 2)
+	for _, action := range []string{"mouse_click", "keyboard_type"} {
+		go func() {
+			decision, _ := confirm(ctx, action, nil)
+			decisions <- decision
+		}()
+	}
+	deadline := time.Now().Add(2 * time.Second)
+	for !strings.Contains(out.String(), "? [y]es") {
+		if time.Now().After(deadline) {
+			t.Fatal("no prompt shown")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	cancel()
+	for i := 0; i < 2; i++ {
+		select {
+		case decision := <-decisions:
+			if decision != FilterDeny {
+				t.Errorf("cancelled call decision = %v, want FilterDeny", decision)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("cancelled call still waiting for an answer")
+		}
+	}
+}
+
+// syncBuffer is a bytes.Buffer safe for concurrent use.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
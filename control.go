@@ -0,0 +1,138 @@
+package cua
+
+import (
+	"context"
+	"sync"
+)
+
+// runControl tracks in-flight runs so they can be paused, resumed, or stopped
+// from outside the run goroutine (e.g., by the control server).
+type runControl struct {
+	mu      sync.Mutex
+	paused  bool
+	resume  chan struct{} // closed when the agent is resumed
+	cancels map[int]context.CancelFunc
+	nextID  int
+	closed  bool           // set by close; no new runs are tracked
+	runs    sync.WaitGroup // in-flight runs, waited for by close
+}
+
+func newRunControl() *runControl {
+	return &runControl{cancels: make(map[int]context.CancelFunc)}
+}
+
+// track registers a run and returns its cancellable context and a function
+// that must be called when the run finishes. It returns ErrClosed once close
+// has been called.
+func (rc *runControl) track(ctx context.Context) (context.Context, func(), error) {
+	rc.mu.Lock()
+	if rc.closed {
+		rc.mu.Unlock()
+		return nil, nil, ErrClosed
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	id := rc.nextID
+	rc.nextID++
+	rc.cancels[id] = cancel
+	rc.runs.Add(1)
+	rc.mu.Unlock()
+
+	return ctx, func() {
+		rc.mu.Lock()
+		delete(rc.cancels, id)
+		rc.mu.Unlock()
+		cancel()
+		rc.runs.Done()
+	}, nil
+}
+
+// running returns the number of in-flight runs.
+func (rc *runControl) running() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return len(rc.cancels)
+}
+
+// stop cancels all in-flight runs and returns how many were cancelled.
+func (rc *runControl) stop() int {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	for _, cancel := range rc.cancels {
+		cancel()
+	}
+	return len(rc.cancels)
+}
+
+// close cancels all in-flight runs, rejects new ones, and waits for the
+// cancelled runs to finish.
+func (rc *runControl) close() {
+	rc.mu.Lock()
+	rc.closed = true
+	for _, cancel := range rc.cancels {
+		cancel()
+	}
+	rc.mu.Unlock()
+	rc.runs.Wait()
+}
+
+func (rc *runControl) pause() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if !rc.paused {
+		rc.paused = true
+		rc.resume = make(chan struct{})
+	}
+}
+
+func (rc *runControl) unpause() {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if rc.paused {
+		rc.paused = false
+		close(rc.resume)
+	}
+}
+
+func (rc *runControl) isPaused() bool {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	return rc.paused
+}
+
+// waitIfPaused blocks while the agent is paused or until ctx is done.
+func (rc *runControl) waitIfPaused(ctx context.Context) error {
+	rc.mu.Lock()
+	paused, resume := rc.paused, rc.resume
+	rc.mu.Unlock()
+	if !paused {
+		return nil
+	}
+
+	select {
+	case <-resume:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Pause suspends all runs before their next tool call.
+// The in-progress tool call, if any, completes first.
+func (c *CUA) Pause() {
+	c.control.pause()
+}
+
+// Resume continues runs suspended by Pause.
+func (c *CUA) Resume() {
+	c.control.unpause()
+}
+
+// Paused reports whether the agent is paused.
+func (c *CUA) Paused() bool {
+	return c.control.isPaused()
+}
+
+// Stop cancels all in-flight runs. Each cancelled run returns a context error.
+func (c *CUA) Stop() {
+	c.control.stop()
+}
@@ -0,0 +1,135 @@
+package cua
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ControlHandler returns the HTTP handler behind the control server.
+// It exposes the following endpoints, all requiring "Authorization: Bearer <token>":
+//
+//	GET  /status     - running runs, pause state, and usage statistics (JSON)
+//	POST /pause      - pause runs before their next tool call
+//	POST /resume     - resume paused runs
+//	POST /stop       - cancel all in-flight runs
+//	GET  /screenshot - the most recent screenshot sent to the model (JPEG)
+//
+// Use it to mount the control plane in your own server; WithControlServer
+// starts a dedicated one.
+func (c *CUA) ControlHandler(token string) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("/status", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, map[string]interface{}{
+			"running": c.control.running(),
+			"paused":  c.control.isPaused(),
+			"usage":   c.Usage(),
+		})
+	})
+
+	mux.HandleFunc("/pause", c.controlAction(func() map[string]interface{} {
+		c.Pause()
+		return map[string]interface{}{"paused": true}
+	}))
+
+	mux.HandleFunc("/resume", c.controlAction(func() map[string]interface{} {
+		c.Resume()
+		return map[string]interface{}{"paused": false}
+	}))
+
+	mux.HandleFunc("/stop", c.controlAction(func() map[string]interface{} {
+		return map[string]interface{}{"stopped": c.control.stop()}
+	}))
+
+	mux.HandleFunc("/screenshot", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		frame := c.lastFrame()
+		if frame == nil {
+			http.Error(w, "no screenshot captured yet", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "image/jpeg")
+		_, _ = w.Write(frame)
+	})
+
+	return requireToken(token, mux)
+}
+
+// controlAction wraps a state-changing control endpoint.
+func (c *CUA) controlAction(action func() map[string]interface{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		writeJSON(w, action())
+	}
+}
+
+// frameSource is implemented by tools that keep the last screenshot sent to
+// the model, such as the screenshot tool.
+type frameSource interface {
+	LastFrame() []byte
+}
+
+// lastFrame returns the most recent screenshot from the screenshot tool.
+func (c *CUA) lastFrame() []byte {
+	for _, t := range c.tools {
+		if managed, ok := t.(*managedTool); ok {
+			if source, ok := managed.Tool.(frameSource); ok {
+				return source.LastFrame()
+			}
+		}
+	}
+	return nil
+}
+
+// startControlServer starts the control server configured by WithControlServer.
+func (c *CUA) startControlServer() error {
+	if c.config.ControlServerToken == "" {
+		return fmt.Errorf("control server requires a token")
+	}
+
+	listener, err := net.Listen("tcp", c.config.ControlServerAddr)
+	if err != nil {
+		return fmt.Errorf("failed to start control server: %w", err)
+	}
+
+	c.controlServer = &http.Server{
+		Handler:           c.ControlHandler(c.config.ControlServerToken),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	// Serve returns when the server is shut down; the agent keeps working
+	// without the control plane if it fails
+	go func() { _ = c.controlServer.Serve(listener) }()
+	return nil
+}
+
+// requireToken rejects requests without the expected bearer token.
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		provided := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if token == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,133 @@
+package cua
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const testToken = "s3cret"
+
+// frameTool is a fake screenshot tool holding a fixed last frame.
+type frameTool struct {
+	fakeTool
+	frame []byte
+}
+
+func (t *frameTool) LastFrame() []byte { return t.frame }
+
+// controlRequest sends a request to h and returns the recorded response.
+func controlRequest(h http.Handler, method, path, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, nil)
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestControlHandlerAuth(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+
+	tests := []struct {
+		name        string
+		handlerTok  string
+		requestTok  string
+		wantStatus  int
+		method      string
+		requestPath string
+	}{
+		{"missing token", testToken, "", http.StatusUnauthorized, http.MethodGet, "/status"},
+		{"wrong token", testToken, "nope", http.StatusUnauthorized, http.MethodGet, "/status"},
+		{"empty server token", "", "", http.StatusUnauthorized, http.MethodGet, "/status"},
+		{"valid token", testToken, testToken, http.StatusOK, http.MethodGet, "/status"},
+		{"wrong method", testToken, testToken, http.StatusMethodNotAllowed, http.MethodGet, "/pause"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := controlRequest(c.ControlHandler(tt.handlerTok), tt.method, tt.requestPath, tt.requestTok)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestControlHandlerPauseResumeStop(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	h := c.ControlHandler(testToken)
+
+	if rec := controlRequest(h, http.MethodPost, "/pause", testToken); rec.Code != http.StatusOK {
+		t.Fatalf("/pause status = %d", rec.Code)
+	}
+	if !c.Paused() {
+		t.Error("agent not paused after /pause")
+	}
+
+	var status struct {
+		Running int  `json:"running"`
+		Paused  bool `json:"paused"`
+	}
+	rec := controlRequest(h, http.MethodGet, "/status", testToken)
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("/status body %q: %v", rec.Body.String(), err)
+	}
+	if !status.Paused {
+		t.Error("/status does not report the pause")
+	}
+
+	controlRequest(h, http.MethodPost, "/resume", testToken)
+	if c.Paused() {
+		t.Error("agent still paused after /resume")
+	}
+
+	ctx, done, err := c.control.track(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer done()
+	rec = controlRequest(h, http.MethodPost, "/stop", testToken)
+	var stopped struct {
+		Stopped int `json:"stopped"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &stopped); err != nil {
+		t.Fatalf("/stop body %q: %v", rec.Body.String(), err)
+	}
+	if stopped.Stopped != 1 {
+		t.Errorf("/stop stopped %d runs, want 1", stopped.Stopped)
+	}
+	if ctx.Err() == nil {
+		t.Error("run context not cancelled by /stop")
+	}
+}
+
+func TestControlHandlerScreenshot(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	h := c.ControlHandler(testToken)
+
+	if rec := controlRequest(h, http.MethodGet, "/screenshot", testToken); rec.Code != http.StatusNotFound {
+		t.Errorf("status without a frame = %d, want 404", rec.Code)
+	}
+
+	frame := []byte{0xFF, 0xD8, 0xFF, 0xE0, 'j', 'p', 'g'}
+	c.tools = c.wrapTools([]interfaces.Tool{
+		&fakeTool{name: "mouse_click"},
+		&frameTool{fakeTool: fakeTool{name: "screen_capture"}, frame: frame},
+	})
+
+	rec := controlRequest(h, http.MethodGet, "/screenshot", testToken)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "image/jpeg" {
+		t.Errorf("Content-Type = %q, want image/jpeg", ct)
+	}
+	if got := rec.Body.Bytes(); string(got) != string(frame) {
+		t.Errorf("body = %v, want the last frame", got)
+	}
+}
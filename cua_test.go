@@ -0,0 +1,84 @@
+package cua
+
+import (
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+
+	"github.com/anxuanzi/cua/internal/tools"
+)
+
+// toolNames returns the names of toolList, keyed for lookup.
+func toolNames(toolList []interfaces.Tool) map[string]bool {
+	names := make(map[string]bool, len(toolList))
+	for _, t := range toolList {
+		names[t.Name()] = true
+	}
+	return names
+}
+
+func TestCreateToolsVision(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Option
+		wantScreenshot bool
+		wantRegion     bool
+	}{
+		{"default", nil, true, false},
+		{"vision with capture region", []Option{WithDefaultCaptureRegion(0, 0, 500, 500)}, true, true},
+		{"vision disabled", []Option{WithVisionDisabled()}, false, false},
+		{"vision disabled ignores capture region", []Option{WithVisionDisabled(), WithDefaultCaptureRegion(0, 0, 500, 500)}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			for _, opt := range tt.opts {
+				opt(cfg)
+			}
+			toolList := createTools(cfg, newToolState(cfg))
+			names := toolNames(toolList)
+
+			if names["screen_capture"] != tt.wantScreenshot {
+				t.Errorf("screen_capture offered = %v, want %v", names["screen_capture"], tt.wantScreenshot)
+			}
+			for _, name := range []string{"mouse_click", "keyboard_type", "keyboard_press", "screen_info", "app_launch"} {
+				if !names[name] {
+					t.Errorf("tool %s missing", name)
+				}
+			}
+
+			var click *tools.ClickTool
+			for _, tool := range toolList {
+				if c, ok := tool.(*tools.ClickTool); ok {
+					click = c
+				}
+			}
+			if click == nil {
+				t.Fatal("no click tool")
+			}
+			if _, got := click.State.CaptureRegion(); got != tt.wantRegion {
+				t.Errorf("capture region set = %v, want %v", got, tt.wantRegion)
+			}
+		})
+	}
+}
+
+func TestCreateToolsTyping(t *testing.T) {
+	cfg := defaultConfig()
+	WithKeyboardLayout("de")(cfg)
+	WithTypeFileRoot("/srv/forms")(cfg)
+	WithPasteThreshold(500)(cfg)
+
+	var typeTool *tools.TypeTool
+	for _, tool := range createTools(cfg, newToolState(cfg)) {
+		if tt, ok := tool.(*tools.TypeTool); ok {
+			typeTool = tt
+		}
+	}
+	if typeTool == nil {
+		t.Fatal("no type tool")
+	}
+	if typeTool.KeyboardLayout != "de" || typeTool.FileRoot != "/srv/forms" || typeTool.PasteThreshold != 500 {
+		t.Errorf("type tool = %+v, want layout de, file root /srv/forms, paste threshold 500", typeTool)
+	}
+}
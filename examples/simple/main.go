@@ -64,6 +64,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create CUA: %v", err)
 	}
+	defer agent.Close()
 
 	ctx := context.Background()
 
@@ -36,6 +36,7 @@ func main() {
 	if err != nil {
 		log.Fatalf("Failed to create CUA: %v", err)
 	}
+	defer agent.Close()
 
 	ctx := context.Background()
 
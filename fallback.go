@@ -0,0 +1,116 @@
+package cua
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// fallback is an agent for a model configured with WithModelFallback.
+type fallback struct {
+	model string
+	agent *agent.Agent
+}
+
+// attemptTotals accumulates usage across a run's attempts.
+type attemptTotals struct {
+	usage     *TokenUsage
+	llmCalls  int
+	toolCalls int
+	timeMs    int64
+}
+
+// add adds another attempt's usage to the totals.
+func (t *attemptTotals) add(other attemptTotals) {
+	if other.usage != nil {
+		if t.usage == nil {
+			t.usage = &TokenUsage{}
+		}
+		t.usage.InputTokens += other.usage.InputTokens
+		t.usage.OutputTokens += other.usage.OutputTokens
+		t.usage.TotalTokens += other.usage.TotalTokens
+		t.usage.ReasoningTokens += other.usage.ReasoningTokens
+	}
+	t.llmCalls += other.llmCalls
+	t.toolCalls += other.toolCalls
+	t.timeMs += other.timeMs
+}
+
+// trackAttempt records the usage of one agent attempt in the cumulative
+// statistics and returns it. Usage is tracked even on error - we want to know
+// what was consumed.
+func (c *CUA) trackAttempt(resp *interfaces.AgentResponse, start time.Time) attemptTotals {
+	// Calculate execution time regardless of success/failure
+	totals := attemptTotals{timeMs: time.Since(start).Milliseconds()}
+
+	if resp != nil {
+		// Response available - extract full details
+		if resp.Usage != nil {
+			totals.usage = &TokenUsage{
+				InputTokens:     resp.Usage.InputTokens,
+				OutputTokens:    resp.Usage.OutputTokens,
+				TotalTokens:     resp.Usage.TotalTokens,
+				ReasoningTokens: resp.Usage.ReasoningTokens,
+			}
+		}
+		// ExecutionSummary is a struct (not pointer), so always accessible
+		totals.llmCalls = resp.ExecutionSummary.LLMCalls
+		totals.toolCalls = resp.ExecutionSummary.ToolCalls
+		// Use reported time if available, otherwise use our measured time
+		if resp.ExecutionSummary.ExecutionTimeMs > 0 {
+			totals.timeMs = resp.ExecutionSummary.ExecutionTimeMs
+		}
+	}
+
+	// Always track the run, even if usage details are unavailable
+	c.usageStats.Add(totals.usage, totals.llmCalls, totals.toolCalls, totals.timeMs)
+
+	// Check token limit and trigger warning if needed
+	c.checkTokenLimit()
+
+	return totals
+}
+
+// shouldEscalate reports whether an attempt that ended with err should be
+// retried on a fallback model. Only failed attempts escalate: a run that ended
+// without error may already have changed the desktop, and repeating it would
+// repeat its actions. Cancelled and timed-out runs are not retried since their
+// context is done, nor are runs stopped on purpose: by the action limit, for
+// help, or by plan rejection.
+func shouldEscalate(err error) bool {
+	if err == nil {
+		return false
+	}
+	switch resultReason(err) {
+	case ReasonCancelled, ReasonTimeout, ReasonMaxActions, ReasonNeedsHelp, ReasonPlanRejected:
+		return false
+	}
+	return true
+}
+
+// escalationTask builds the task for a fallback attempt, carrying what the
+// previous attempt achieved so the new model can continue rather than restart.
+func escalationTask(task, prevModel string, reason ResultReason, resp *interfaces.AgentResponse, err error) string {
+	var b strings.Builder
+	b.WriteString(task)
+	fmt.Fprintf(&b, "\n\n<previous_attempt>\nA previous attempt with model %s did not finish (%s).", prevModel, reason)
+	if err != nil {
+		fmt.Fprintf(&b, "\nError: %v", err)
+	}
+	if resp != nil && resp.Content != "" {
+		fmt.Fprintf(&b, "\nIts last response:\n%s", resp.Content)
+	}
+	b.WriteString("\nSome steps may already be done. Take a screenshot to check the current state before continuing.\n</previous_attempt>")
+	return b.String()
+}
+
+// modelName returns the configured model, or the provider's default.
+func (c *CUA) modelName() string {
+	if c.config.Model != "" {
+		return c.config.Model
+	}
+	return defaultModels[c.config.Provider]
+}
@@ -0,0 +1,51 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestShouldEscalate(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"completed", nil, false},
+		{"cancelled", context.Canceled, false},
+		{"timeout", context.DeadlineExceeded, false},
+		{"rate limited", errors.New("429 too many requests"), true},
+		{"provider error", errors.New("model overloaded"), true},
+		{"panic", &PanicError{Value: "boom"}, true},
+		{"max actions", ErrMaxActions, false},
+		{"needs help", &NeedsHelpError{Reason: "captcha"}, false},
+		{"plan rejected", ErrPlanRejected, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := shouldEscalate(tt.err); got != tt.want {
+				t.Errorf("shouldEscalate(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEscalationTask(t *testing.T) {
+	resp := &interfaces.AgentResponse{Content: "Opened the settings page"}
+	err := errors.New("model overloaded")
+
+	got := escalationTask("Enable dark mode", "flash", ReasonProviderError, resp, err)
+
+	for _, want := range []string{"Enable dark mode", "flash", "provider_error", "model overloaded", "Opened the settings page"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("escalationTask() = %q, missing %q", got, want)
+		}
+	}
+	if !strings.HasPrefix(got, "Enable dark mode") {
+		t.Errorf("escalationTask() should start with the original task, got %q", got)
+	}
+}
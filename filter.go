@@ -0,0 +1,45 @@
+package cua
+
+import (
+	"encoding/json"
+)
+
+// FilterDecision is the outcome of an ActionFilter.
+type FilterDecision int
+
+const (
+	FilterAllow  FilterDecision = iota // Execute the action unchanged
+	FilterDeny                         // Block the action; the model receives an error observation
+	FilterModify                       // Execute the action with the returned arguments
+)
+
+// ActionFilter inspects a tool call before it executes.
+// action is the tool name (e.g., "keyboard_type") and args are the decoded arguments.
+// Return FilterModify with a new argument map to rewrite the call.
+type ActionFilter func(action string, args map[string]any) (FilterDecision, map[string]any)
+
+// applyActionFilter runs the configured filter for a tool call.
+// It returns the (possibly rewritten) arguments and whether the call may proceed.
+func applyActionFilter(filter ActionFilter, action, argsJSON string) (string, bool, error) {
+	args := make(map[string]any)
+	if argsJSON != "" {
+		if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+			// Let the tool report malformed arguments itself
+			return argsJSON, true, nil
+		}
+	}
+
+	decision, modified := filter(action, args)
+	switch decision {
+	case FilterDeny:
+		return argsJSON, false, nil
+	case FilterModify:
+		data, err := json.Marshal(modified)
+		if err != nil {
+			return argsJSON, false, err
+		}
+		return string(data), true, nil
+	default:
+		return argsJSON, true, nil
+	}
+}
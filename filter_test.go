@@ -0,0 +1,101 @@
+package cua
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestApplyActionFilter(t *testing.T) {
+	tests := []struct {
+		name        string
+		filter      ActionFilter
+		args        string
+		wantArgs    string
+		wantAllowed bool
+	}{
+		{
+			name:        "allow",
+			filter:      func(string, map[string]any) (FilterDecision, map[string]any) { return FilterAllow, nil },
+			args:        `{"text":"hi"}`,
+			wantArgs:    `{"text":"hi"}`,
+			wantAllowed: true,
+		},
+		{
+			name:     "deny",
+			filter:   func(string, map[string]any) (FilterDecision, map[string]any) { return FilterDeny, nil },
+			args:     `{"text":"hi"}`,
+			wantArgs: `{"text":"hi"}`,
+		},
+		{
+			name: "modify",
+			filter: func(_ string, args map[string]any) (FilterDecision, map[string]any) {
+				args["text"] = "redacted"
+				return FilterModify, args
+			},
+			args:        `{"text":"secret"}`,
+			wantArgs:    `{"text":"redacted"}`,
+			wantAllowed: true,
+		},
+		{
+			name:        "malformed arguments are left to the tool",
+			filter:      func(string, map[string]any) (FilterDecision, map[string]any) { return FilterDeny, nil },
+			args:        `{not json`,
+			wantArgs:    `{not json`,
+			wantAllowed: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, allowed, err := applyActionFilter(tt.filter, "keyboard_type", tt.args)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.wantArgs || allowed != tt.wantAllowed {
+				t.Errorf("got (%s, %v), want (%s, %v)", got, allowed, tt.wantArgs, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestExecuteToolActionFilter(t *testing.T) {
+	t.Run("deny", func(t *testing.T) {
+		cfg := defaultConfig()
+		WithActionFilter(func(action string, _ map[string]any) (FilterDecision, map[string]any) {
+			if action == "keyboard_type" {
+				return FilterDeny, nil
+			}
+			return FilterAllow, nil
+		})(cfg)
+		c := newTestCUA(cfg)
+		tool := &fakeTool{name: "keyboard_type"}
+
+		result, err := c.executeTool(context.Background(), tool, `{"text":"hi"}`)
+		if err != nil {
+			t.Fatalf("executeTool: %v", err)
+		}
+		if !strings.Contains(result, "action denied by filter: keyboard_type") {
+			t.Errorf("result = %s, want a denial observation", result)
+		}
+		if n := tool.calls.Load(); n != 0 {
+			t.Errorf("denied tool ran %d times", n)
+		}
+	})
+
+	t.Run("modify", func(t *testing.T) {
+		cfg := defaultConfig()
+		WithActionFilter(func(_ string, args map[string]any) (FilterDecision, map[string]any) {
+			args["text"] = "redacted"
+			return FilterModify, args
+		})(cfg)
+		c := newTestCUA(cfg)
+		tool := &fakeTool{name: "keyboard_type"}
+
+		if _, err := c.executeTool(context.Background(), tool, `{"text":"secret"}`); err != nil {
+			t.Fatalf("executeTool: %v", err)
+		}
+		if got := tool.lastInput(); got != `{"text":"redacted"}` {
+			t.Errorf("tool received %s, want the rewritten arguments", got)
+		}
+	})
+}
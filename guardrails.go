@@ -0,0 +1,109 @@
+package cua
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anxuanzi/cua/internal/safety"
+	"github.com/anxuanzi/cua/pkg/screen"
+)
+
+// observationTools only read state, so guardrails never block them unless a
+// rate limit is set for them explicitly (see WithActionRateLimits).
+var observationTools = map[string]bool{
+	"screen_capture": true,
+	"screen_info":    true,
+	"app_list":       true,
+}
+
+// captureScreen captures a screen for the lock screen guardrail.
+// It is a variable so the guardrail can be driven by a fake capture.
+var captureScreen = screen.Capture
+
+// checkGuardrails returns an error describing why the action tool may not run
+// now, or nil if it may.
+func (c *CUA) checkGuardrails(toolName string) error {
+	if c.rateLimited(toolName) && !c.rateLimiter.Allowed(toolName, time.Now()) {
+		limit, _ := c.rateLimiter.Limit(toolName)
+		return fmt.Errorf("rate limit for %s exceeded (%d per minute)", toolName, limit)
+	}
+
+	if observationTools[toolName] {
+		return nil
+	}
+
+	if hours := c.config.OperatingHours; hours != nil && !hours.Contains(time.Now()) {
+		return fmt.Errorf("actions are not allowed outside operating hours (%s-%s)",
+			formatClock(hours.Start), formatClock(hours.End))
+	}
+
+	if c.config.BlockOnLockScreen {
+		return c.checkLockScreen(time.Now())
+	}
+
+	return nil
+}
+
+// lockScreenCheckInterval is how long a lock screen check's verdict is reused.
+const lockScreenCheckInterval = 2 * time.Second
+
+// lockScreenCheck caches the verdict of the last lock screen check.
+type lockScreenCheck struct {
+	mu     sync.Mutex
+	screen int       // Screen that was checked
+	at     time.Time // When it was checked; zero if never
+	err    error     // Why actions were blocked, or nil
+}
+
+// checkLockScreen returns an error if the screen the tools act on appears
+// locked or can't be captured. A verdict for the same screen is reused for
+// lockScreenCheckInterval rather than capturing before every action.
+func (c *CUA) checkLockScreen(now time.Time) error {
+	screenIndex := c.config.ScreenIndex
+	if c.toolState != nil {
+		screenIndex = c.toolState.ScreenIndex()
+	}
+
+	check := &c.lockCheck
+	check.mu.Lock()
+	defer check.mu.Unlock()
+	if !check.at.IsZero() && check.screen == screenIndex && now.Sub(check.at) < lockScreenCheckInterval {
+		return check.err
+	}
+
+	var err error
+	if capture, captureErr := captureScreen(screenIndex); captureErr != nil {
+		err = fmt.Errorf("cannot verify the screen is unlocked: %w", captureErr)
+	} else if safety.IsLockScreen(capture.Image) {
+		err = fmt.Errorf("the screen appears to be locked or showing a screensaver")
+	}
+	check.screen, check.at, check.err = screenIndex, now, err
+	return err
+}
+
+// rateLimited reports whether calls of toolName count against a rate limit.
+// The default limit only covers actions; observations are limited only when
+// listed explicitly.
+func (c *CUA) rateLimited(toolName string) bool {
+	if c.rateLimiter == nil {
+		return false
+	}
+	_, explicit := c.rateLimiter.Limit(toolName)
+	return explicit || !observationTools[toolName]
+}
+
+// recordRateLimitedCall counts a call of toolName that passed every check
+// against its rate limit. Calls denied by a guardrail, filter, confirmation,
+// or argument validation are never counted, so a model retrying bad calls
+// can't use up the budget of valid ones.
+func (c *CUA) recordRateLimitedCall(toolName string) {
+	if c.rateLimited(toolName) {
+		c.rateLimiter.Record(toolName, time.Now())
+	}
+}
+
+// formatClock formats an offset from midnight as HH:MM.
+func formatClock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
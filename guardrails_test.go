@@ -0,0 +1,197 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"image"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+
+	"github.com/anxuanzi/cua/internal/safety"
+	"github.com/anxuanzi/cua/pkg/screen"
+)
+
+func TestCheckGuardrailsRateLimit(t *testing.T) {
+	tests := []struct {
+		name         string
+		defaultLimit int
+		limits       map[string]int
+		tool         string
+		calls        int
+		wantBlocked  int // Calls blocked out of calls
+	}{
+		{"action over default limit", 2, nil, "mouse_click", 4, 2},
+		{"observation exempt from default", 1, nil, "screen_capture", 5, 0},
+		{"observation limited explicitly", 0, map[string]int{"screen_capture": 3}, "screen_capture", 5, 2},
+		{"action exempted explicitly", 1, map[string]int{"keyboard_type": 0}, "keyboard_type", 5, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestCUA(defaultConfig())
+			c.rateLimiter = safety.NewRateLimiter(tt.defaultLimit, tt.limits)
+
+			blocked := 0
+			for i := 0; i < tt.calls; i++ {
+				if err := c.checkGuardrails(tt.tool); err != nil {
+					if !strings.Contains(err.Error(), "rate limit for "+tt.tool+" exceeded") {
+						t.Fatalf("unexpected error: %v", err)
+					}
+					blocked++
+					continue
+				}
+				c.recordRateLimitedCall(tt.tool)
+			}
+			if blocked != tt.wantBlocked {
+				t.Errorf("blocked %d of %d calls, want %d", blocked, tt.calls, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestCheckGuardrailsOperatingHours(t *testing.T) {
+	tests := []struct {
+		name        string
+		start, end  time.Duration
+		tool        string
+		wantBlocked bool
+	}{
+		{"action inside window", 0, 24 * time.Hour, "mouse_click", false},
+		{"action outside window", 0, 0, "mouse_click", true},
+		{"observation outside window", 0, 0, "screen_capture", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			WithOperatingHours(tt.start, tt.end)(cfg)
+			c := newTestCUA(cfg)
+
+			err := c.checkGuardrails(tt.tool)
+			if (err != nil) != tt.wantBlocked {
+				t.Fatalf("checkGuardrails = %v, want blocked %v", err, tt.wantBlocked)
+			}
+			if err != nil && !strings.Contains(err.Error(), "outside operating hours (00:00-00:00)") {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckGuardrailsLockScreen(t *testing.T) {
+	locked := image.NewGray(image.Rect(0, 0, 320, 200))
+	unlocked := image.NewGray(image.Rect(0, 0, 320, 200))
+	for i := range unlocked.Pix {
+		unlocked.Pix[i] = byte(i * 31)
+	}
+
+	tests := []struct {
+		name        string
+		capture     image.Image
+		captureErr  error
+		tool        string
+		wantErr     string
+		wantCapture bool
+	}{
+		{"unlocked screen permits input", unlocked, nil, "keyboard_type", "", true},
+		{"locked screen blocks input", locked, nil, "keyboard_type", "appears to be locked", true},
+		{"failed capture blocks input", nil, errors.New("no display"), "mouse_click", "cannot verify the screen is unlocked", true},
+		{"observations are not checked", locked, nil, "screen_capture", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captured := false
+			orig := captureScreen
+			t.Cleanup(func() { captureScreen = orig })
+			captureScreen = func(int) (*screen.CaptureResult, error) {
+				captured = true
+				if tt.captureErr != nil {
+					return nil, tt.captureErr
+				}
+				return &screen.CaptureResult{Image: tt.capture}, nil
+			}
+
+			cfg := defaultConfig()
+			WithBlockOnLockScreen(true)(cfg)
+			err := newTestCUA(cfg).checkGuardrails(tt.tool)
+
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("checkGuardrails = %v, want nil", err)
+			}
+			if tt.wantErr != "" && (err == nil || !strings.Contains(err.Error(), tt.wantErr)) {
+				t.Errorf("checkGuardrails = %v, want %q", err, tt.wantErr)
+			}
+			if captured != tt.wantCapture {
+				t.Errorf("captured = %v, want %v", captured, tt.wantCapture)
+			}
+		})
+	}
+}
+
+func TestLockScreenCheckUsesActiveScreenAndCaches(t *testing.T) {
+	var captured []int
+	orig := captureScreen
+	t.Cleanup(func() { captureScreen = orig })
+	captureScreen = func(index int) (*screen.CaptureResult, error) {
+		captured = append(captured, index)
+		return &screen.CaptureResult{Image: image.NewGray(image.Rect(0, 0, 320, 200))}, nil
+	}
+
+	cfg := defaultConfig()
+	WithBlockOnLockScreen(true)(cfg)
+	c := newTestCUA(cfg)
+	c.toolState = newToolState(cfg)
+	c.toolState.SetScreenIndex(1)
+
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	checks := []time.Duration{0, time.Second, lockScreenCheckInterval - time.Millisecond}
+	for _, at := range checks {
+		if err := c.checkLockScreen(now.Add(at)); err == nil {
+			t.Fatalf("check at +%v permitted input on a locked screen", at)
+		}
+	}
+	if want := []int{1}; !reflect.DeepEqual(captured, want) {
+		t.Fatalf("captured screens %v within the interval, want %v", captured, want)
+	}
+
+	// A new screen or an expired verdict is checked again
+	c.toolState.SetScreenIndex(0)
+	_ = c.checkLockScreen(now.Add(time.Second))
+	_ = c.checkLockScreen(now.Add(time.Second + lockScreenCheckInterval))
+	if want := []int{1, 0, 0}; !reflect.DeepEqual(captured, want) {
+		t.Errorf("captured screens %v, want %v", captured, want)
+	}
+}
+
+func TestRateLimitCountsOnlyExecutedCalls(t *testing.T) {
+	cfg := defaultConfig()
+	WithActionRateLimit(2)(cfg)
+	WithActionFilter(func(_ string, args map[string]any) (FilterDecision, map[string]any) {
+		if args["text"] == "denied" {
+			return FilterDeny, nil
+		}
+		return FilterAllow, nil
+	})(cfg)
+	c := newTestCUA(cfg)
+	c.rateLimiter = safety.NewRateLimiter(cfg.ActionRateLimit, nil)
+	tool := &fakeTool{name: "keyboard_type", params: map[string]interfaces.ParameterSpec{
+		"text": {Type: "string", Required: true},
+	}}
+
+	// Denied and invalid calls don't use up the budget
+	for _, args := range []string{`{"text":"denied"}`, `{"text":42}`, `{"text":"denied"}`, `{}`} {
+		if _, err := c.executeTool(context.Background(), tool, args); err != nil {
+			t.Fatalf("executeTool(%s): %v", args, err)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		if _, err := c.executeTool(context.Background(), tool, `{"text":"hi"}`); err != nil {
+			t.Fatalf("executeTool: %v", err)
+		}
+	}
+	if n := tool.calls.Load(); n != 2 {
+		t.Errorf("tool ran %d times, want the limit of 2 valid calls", n)
+	}
+}
@@ -0,0 +1,87 @@
+package cua
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// fakeTool is a tool that records its calls and returns a fixed result.
+type fakeTool struct {
+	name   string
+	params map[string]interfaces.ParameterSpec
+	result string
+	panics interface{} // If non-nil, Execute panics with this value
+	calls  atomic.Int32
+	input  atomic.Value // Arguments of the last call
+}
+
+func (t *fakeTool) Name() string        { return t.name }
+func (t *fakeTool) Description() string { return "fake tool for tests" }
+
+func (t *fakeTool) Parameters() map[string]interfaces.ParameterSpec { return t.params }
+
+func (t *fakeTool) Execute(_ context.Context, input string) (string, error) {
+	t.calls.Add(1)
+	t.input.Store(input)
+	if t.panics != nil {
+		panic(t.panics)
+	}
+	if t.result == "" {
+		return `{"success":true}`, nil
+	}
+	return t.result, nil
+}
+
+// lastInput returns the arguments of the last call, or "" if there was none.
+func (t *fakeTool) lastInput() string {
+	s, _ := t.input.Load().(string)
+	return s
+}
+
+func (t *fakeTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// newTestCUA returns a CUA with cfg and no LLM, enough to drive tool calls.
+func newTestCUA(cfg *Config) *CUA {
+	return &CUA{config: cfg, usageStats: &UsageStats{}, control: newRunControl()}
+}
+
+// fakeLLM is a model that answers every prompt with a fixed response and
+// records the last prompt and system message it was given.
+type fakeLLM struct {
+	response string
+	err      error
+	usage    *interfaces.TokenUsage // Reported by the detailed methods
+
+	prompt, system string
+	calls          int
+}
+
+func (m *fakeLLM) Generate(_ context.Context, prompt string, options ...interfaces.GenerateOption) (string, error) {
+	opts := &interfaces.GenerateOptions{}
+	for _, opt := range options {
+		opt(opts)
+	}
+	m.calls++
+	m.prompt, m.system = prompt, opts.SystemMessage
+	return m.response, m.err
+}
+
+func (m *fakeLLM) GenerateWithTools(ctx context.Context, prompt string, _ []interfaces.Tool, options ...interfaces.GenerateOption) (string, error) {
+	return m.Generate(ctx, prompt, options...)
+}
+
+func (m *fakeLLM) GenerateDetailed(ctx context.Context, prompt string, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := m.Generate(ctx, prompt, options...)
+	return &interfaces.LLMResponse{Content: content, Usage: m.usage}, err
+}
+
+func (m *fakeLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, _ []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	return m.GenerateDetailed(ctx, prompt, options...)
+}
+
+func (m *fakeLLM) Name() string            { return "fake" }
+func (m *fakeLLM) SupportsStreaming() bool { return false }
@@ -18,6 +18,29 @@ func DenormalizeXY(normX, normY int, screen ScreenInfo) (pixelX, pixelY int) {
 	return point.X, point.Y
 }
 
+// Snap rounds a screen pixel point to the nearest multiple of grid pixels,
+// measured from the screen's origin, keeping it on the screen. A grid of 1 or
+// less returns the point unchanged.
+func Snap(pixel Point, screen ScreenInfo, grid int) Point {
+	if grid <= 1 {
+		return pixel
+	}
+	return Point{
+		X: screen.X + snapInt(pixel.X-screen.X, grid, screen.Width-1),
+		Y: screen.Y + snapInt(pixel.Y-screen.Y, grid, screen.Height-1),
+	}
+}
+
+// snapInt rounds val to the nearest multiple of grid, clamped to 0..max.
+// Rounding up past max falls back to the multiple below.
+func snapInt(val, grid, max int) int {
+	snapped := (val + grid/2) / grid * grid
+	if snapped > max {
+		snapped -= grid
+	}
+	return clampInt(snapped, 0, max)
+}
+
 // Normalize converts screen pixel coordinates to normalized 0-1000 coordinates.
 // This is useful for reporting positions back to the model.
 func Normalize(pixel Point, screen ScreenInfo) NormalizedPoint {
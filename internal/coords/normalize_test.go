@@ -0,0 +1,37 @@
+package coords
+
+import "testing"
+
+func TestSnap(t *testing.T) {
+	primary := ScreenInfo{Width: 1920, Height: 1080}
+	secondary := ScreenInfo{X: 1920, Y: 0, Width: 1280, Height: 1024}
+
+	tests := []struct {
+		name   string
+		screen ScreenInfo
+		in     Point
+		grid   int
+		want   Point
+	}{
+		{"disabled", primary, Point{X: 123, Y: 457}, 0, Point{X: 123, Y: 457}},
+		{"grid of one", primary, Point{X: 123, Y: 457}, 1, Point{X: 123, Y: 457}},
+		{"round down", primary, Point{X: 122, Y: 456}, 5, Point{X: 120, Y: 455}},
+		{"round up", primary, Point{X: 123, Y: 458}, 5, Point{X: 125, Y: 460}},
+		{"already on grid", primary, Point{X: 500, Y: 500}, 10, Point{X: 500, Y: 500}},
+		{"right edge stays on screen", primary, Point{X: 1919, Y: 1079}, 5, Point{X: 1915, Y: 1075}},
+		{"origin", primary, Point{}, 5, Point{}},
+		{"relative to screen origin", secondary, Point{X: 1923, Y: 7}, 5, Point{X: 1925, Y: 5}},
+		{"secondary edge", secondary, Point{X: 1920 + 1279, Y: 1023}, 8, Point{X: 1920 + 1272, Y: 1016}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Snap(tt.in, tt.screen, tt.grid)
+			if got != tt.want {
+				t.Errorf("Snap(%v, grid %d) = %v, want %v", tt.in, tt.grid, got, tt.want)
+			}
+			if tt.grid > 1 && ((got.X-tt.screen.X)%tt.grid != 0 || (got.Y-tt.screen.Y)%tt.grid != 0) {
+				t.Errorf("Snap(%v) = %v is not on the %dpx grid", tt.in, got, tt.grid)
+			}
+		})
+	}
+}
@@ -1,39 +1,81 @@
 package coords
 
 import (
+	"sync"
+	"time"
+
 	"github.com/go-vgo/robotgo"
 )
 
+// layoutTTL is how long a cached display layout is trusted. Changes seen by a
+// display watcher invalidate it sooner; the TTL bounds how stale the layout
+// can get when nothing is watching.
+const layoutTTL = 5 * time.Second
+
+// layoutCache holds the display layout so the hot screenshot/click path does
+// not query the OS on every call. It is refreshed after layoutTTL or after
+// InvalidateScreens.
+var layoutCache struct {
+	mu       sync.RWMutex
+	screens  []ScreenInfo
+	loadedAt time.Time
+}
+
+// loadScreens and now are the OS backend and clock behind the layout cache.
+// They are variables so the cache can be driven by a fake backend.
+var (
+	loadScreens = QueryAllScreens
+	now         = time.Now
+)
+
 // GetPrimaryScreen returns information about the primary display.
 func GetPrimaryScreen() ScreenInfo {
 	return GetScreen(0)
 }
 
+// GetScreen returns information about a specific screen by index.
+// Results are served from the display layout cache.
+func GetScreen(index int) ScreenInfo {
+	screens := cachedScreens()
+	if index >= 0 && index < len(screens) {
+		return screens[index]
+	}
+	// Out-of-range indexes are not cached; report whatever the OS returns
+	return queryScreen(index)
+}
+
 // GetScreenCount returns the number of available screens.
 func GetScreenCount() int {
-	// robotgo doesn't expose display count directly
-	// We'll probe for screens until we get an invalid response
-	count := 1
-	for i := 1; i < 10; i++ {
-		rect := robotgo.GetDisplayRect(i)
-		if rect.W == 0 && rect.H == 0 {
-			break
-		}
-		count++
-	}
-	return count
+	return len(cachedScreens())
 }
 
 // GetAllScreens returns information about all available screens.
 func GetAllScreens() []ScreenInfo {
-	count := GetScreenCount()
+	screens := cachedScreens()
+	out := make([]ScreenInfo, len(screens))
+	copy(out, screens)
+	return out
+}
+
+// QueryAllScreens reads information about all screens directly from the OS,
+// bypassing the cache. Use it to detect layout changes.
+func QueryAllScreens() []ScreenInfo {
+	count := queryScreenCount()
 	screens := make([]ScreenInfo, count)
 	for i := 0; i < count; i++ {
-		screens[i] = GetScreen(i)
+		screens[i] = queryScreen(i)
 	}
 	return screens
 }
 
+// InvalidateScreens discards the cached display layout so the next lookup
+// re-reads it from the OS. Call it after a resolution or DPI change.
+func InvalidateScreens() {
+	layoutCache.mu.Lock()
+	layoutCache.screens = nil
+	layoutCache.mu.Unlock()
+}
+
 // GetScreenAt returns the screen containing the given pixel coordinates.
 // Returns the primary screen if no screen contains the point.
 func GetScreenAt(x, y int) ScreenInfo {
@@ -46,3 +88,40 @@ func GetScreenAt(x, y int) ScreenInfo {
 	}
 	return GetPrimaryScreen()
 }
+
+// cachedScreens returns the cached layout, refreshing it when it has been
+// invalidated or is older than layoutTTL.
+func cachedScreens() []ScreenInfo {
+	t := now()
+
+	layoutCache.mu.RLock()
+	screens := layoutCache.screens
+	valid := screens != nil && t.Sub(layoutCache.loadedAt) < layoutTTL
+	layoutCache.mu.RUnlock()
+	if valid {
+		return screens
+	}
+
+	layoutCache.mu.Lock()
+	defer layoutCache.mu.Unlock()
+	if layoutCache.screens == nil || t.Sub(layoutCache.loadedAt) >= layoutTTL {
+		layoutCache.screens = loadScreens()
+		layoutCache.loadedAt = t
+	}
+	return layoutCache.screens
+}
+
+// queryScreenCount returns the number of available screens.
+func queryScreenCount() int {
+	// robotgo doesn't expose display count directly
+	// We'll probe for screens until we get an invalid response
+	count := 1
+	for i := 1; i < 10; i++ {
+		rect := robotgo.GetDisplayRect(i)
+		if rect.W == 0 && rect.H == 0 {
+			break
+		}
+		count++
+	}
+	return count
+}
@@ -6,11 +6,11 @@ import (
 	"github.com/go-vgo/robotgo"
 )
 
-// GetScreen returns information about a specific screen by index.
+// queryScreen reads information about a specific screen by index from the OS.
 // On macOS, robotgo uses LOGICAL coordinates (not physical pixels).
 // This is important: mouse operations (Move, Click) use logical coords,
 // but CaptureImg returns physical (Retina) resolution.
-func GetScreen(index int) ScreenInfo {
+func queryScreen(index int) ScreenInfo {
 	rect := robotgo.GetDisplayRect(index)
 
 	// On macOS, robotgo.GetDisplayRect returns LOGICAL dimensions
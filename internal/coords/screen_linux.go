@@ -6,9 +6,9 @@ import (
 	"github.com/go-vgo/robotgo"
 )
 
-// GetScreen returns information about a specific screen by index.
+// queryScreen reads information about a specific screen by index from the OS.
 // On Linux, behavior depends on the display server (X11 or Wayland).
-func GetScreen(index int) ScreenInfo {
+func queryScreen(index int) ScreenInfo {
 	rect := robotgo.GetDisplayRect(index)
 
 	return ScreenInfo{
@@ -0,0 +1,126 @@
+package coords
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeScreens replaces the layout cache backend with one reporting count
+// displays of the given width, and returns the number of layout loads.
+func fakeScreens(t *testing.T, count *atomic.Int32, width int) *atomic.Int32 {
+	t.Helper()
+	var loads atomic.Int32
+	origLoad, origNow := loadScreens, now
+	t.Cleanup(func() {
+		loadScreens, now = origLoad, origNow
+		InvalidateScreens()
+	})
+	InvalidateScreens()
+
+	loadScreens = func() []ScreenInfo {
+		loads.Add(1)
+		screens := make([]ScreenInfo, count.Load())
+		for i := range screens {
+			screens[i] = ScreenInfo{Index: i, X: i * width, Width: width, Height: 1080, ScaleFactor: 1, IsPrimary: i == 0}
+		}
+		return screens
+	}
+	return &loads
+}
+
+func TestLayoutCacheHits(t *testing.T) {
+	var count atomic.Int32
+	count.Store(2)
+	loads := fakeScreens(t, &count, 1920)
+
+	for i := 0; i < 5; i++ {
+		if got := GetScreenCount(); got != 2 {
+			t.Fatalf("GetScreenCount = %d, want 2", got)
+		}
+		if got := GetScreen(1); got.X != 1920 {
+			t.Fatalf("GetScreen(1).X = %d, want 1920", got.X)
+		}
+		_ = GetAllScreens()
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("layout loaded %d times, want 1", n)
+	}
+}
+
+func TestLayoutCacheRefresh(t *testing.T) {
+	var count atomic.Int32
+	count.Store(1)
+	loads := fakeScreens(t, &count, 1920)
+
+	GetScreenCount()
+	InvalidateScreens()
+	GetScreenCount()
+	if n := loads.Load(); n != 2 {
+		t.Fatalf("after invalidation layout loaded %d times, want 2", n)
+	}
+
+}
+
+func TestLayoutCacheTTL(t *testing.T) {
+	var count atomic.Int32
+	count.Store(1)
+	loads := fakeScreens(t, &count, 1920)
+	clock := time.Unix(1000, 0)
+	now = func() time.Time { return clock }
+
+	GetScreenCount()
+	count.Store(2)
+	clock = clock.Add(layoutTTL - time.Millisecond)
+	if got := GetScreenCount(); got != 1 {
+		t.Errorf("GetScreenCount = %d within the TTL, want cached 1", got)
+	}
+	if n := loads.Load(); n != 1 {
+		t.Errorf("within the TTL layout loaded %d times, want 1", n)
+	}
+
+	clock = clock.Add(time.Millisecond)
+	if got := GetScreenCount(); got != 2 {
+		t.Errorf("GetScreenCount = %d after the TTL, want 2", got)
+	}
+	if n := loads.Load(); n != 2 {
+		t.Errorf("after the TTL layout loaded %d times, want 2", n)
+	}
+}
+
+func TestGetAllScreensReturnsCopy(t *testing.T) {
+	var count atomic.Int32
+	count.Store(1)
+	fakeScreens(t, &count, 1920)
+
+	screens := GetAllScreens()
+	screens[0].Width = 1
+	if got := GetPrimaryScreen().Width; got != 1920 {
+		t.Errorf("mutating GetAllScreens result changed the cache: width = %d", got)
+	}
+}
+
+func TestLayoutCacheConcurrent(t *testing.T) {
+	var count atomic.Int32
+	count.Store(2)
+	fakeScreens(t, &count, 1280)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if j%10 == 0 {
+					InvalidateScreens()
+				}
+				if got := GetScreen(1).Width; got != 1280 {
+					t.Errorf("GetScreen(1).Width = %d, want 1280", got)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,91 @@
+package platform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrAppNotFound is returned by ResolveAppPath when no installed app matches.
+var ErrAppNotFound = errors.New("application not found")
+
+// ResolveAppPath finds the installed application called name so it can be
+// launched directly, without going through launcher UI:
+//
+//   - macOS: the .app bundle in the Applications folders, then Spotlight
+//   - Windows: the registry App Paths entry, then a Start Menu shortcut (.lnk)
+//   - Linux: the .desktop file in the XDG application directories
+//
+// Names are matched case-insensitively. It returns ErrAppNotFound if there is
+// no match.
+func ResolveAppPath(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrAppNotFound
+	}
+	return resolveAppPath(name)
+}
+
+// appCandidates returns the file names to look for: name, and name with ext
+// appended if it doesn't already end in ext, without case-insensitive duplicates.
+func appCandidates(name, ext string) []string {
+	candidates := []string{name}
+	if !strings.EqualFold(filepath.Ext(name), ext) {
+		candidates = append(candidates, name+ext)
+	}
+
+	seen := make(map[string]bool)
+	unique := candidates[:0]
+	for _, c := range candidates {
+		key := strings.ToLower(c)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, c)
+	}
+	return unique
+}
+
+// findInDirs returns the first entry of dirs whose name case-insensitively
+// matches one of candidates, searching dirs in order and up to depth levels
+// of subdirectories below each.
+func findInDirs(dirs, candidates []string, depth int) (string, bool) {
+	want := make(map[string]bool, len(candidates))
+	for _, c := range candidates {
+		want[strings.ToLower(c)] = true
+	}
+
+	for _, dir := range dirs {
+		if path, ok := findInDir(dir, want, depth); ok {
+			return path, true
+		}
+	}
+	return "", false
+}
+
+func findInDir(dir string, want map[string]bool, depth int) (string, bool) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+	// Prefer a match at this level over one in a subdirectory
+	for _, e := range entries {
+		if want[strings.ToLower(e.Name())] {
+			return filepath.Join(dir, e.Name()), true
+		}
+	}
+	if depth <= 0 {
+		return "", false
+	}
+	for _, e := range entries {
+		// Don't descend into bundles; they are matches, not folders to search
+		if e.IsDir() && filepath.Ext(e.Name()) != ".app" {
+			if path, ok := findInDir(filepath.Join(dir, e.Name()), want, depth-1); ok {
+				return path, true
+			}
+		}
+	}
+	return "", false
+}
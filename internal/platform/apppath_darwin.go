@@ -0,0 +1,35 @@
+//go:build darwin
+
+package platform
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// resolveAppPath finds an .app bundle in the Applications folders, falling
+// back to a Spotlight search by display name.
+func resolveAppPath(name string) (string, error) {
+	dirs := []string{"/Applications", "/System/Applications", "/System/Applications/Utilities"}
+	if home, err := os.UserHomeDir(); err == nil {
+		dirs = append(dirs, filepath.Join(home, "Applications"))
+	}
+	if path, ok := findInDirs(dirs, appCandidates(name, ".app"), 1); ok {
+		return path, nil
+	}
+
+	query := "kMDItemContentType == 'com.apple.application-bundle' && kMDItemDisplayName ==[c] '" +
+		strings.ReplaceAll(strings.TrimSuffix(name, ".app"), "'", `\'`) + "'"
+	out, err := exec.Command("mdfind", query).Output()
+	if err != nil {
+		return "", ErrAppNotFound
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if path := strings.TrimSpace(line); path != "" {
+			return path, nil
+		}
+	}
+	return "", ErrAppNotFound
+}
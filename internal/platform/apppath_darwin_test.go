@@ -0,0 +1,20 @@
+//go:build darwin
+
+package platform
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveAppPathKnownApp(t *testing.T) {
+	for _, name := range []string{"Safari", "safari.app"} {
+		path, err := ResolveAppPath(name)
+		if err != nil {
+			t.Fatalf("ResolveAppPath(%q): %v", name, err)
+		}
+		if filepath.Base(path) != "Safari.app" {
+			t.Errorf("ResolveAppPath(%q) = %q, want the Safari bundle", name, path)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+//go:build !darwin && !windows
+
+package platform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// resolveAppPath finds the app's .desktop file in the XDG application
+// directories, by file name or by its Name= entry.
+func resolveAppPath(name string) (string, error) {
+	dirs := applicationDirs()
+	if path, ok := findInDirs(dirs, appCandidates(name, ".desktop"), 1); ok {
+		return path, nil
+	}
+
+	for _, dir := range dirs {
+		matches, _ := filepath.Glob(filepath.Join(dir, "*.desktop"))
+		for _, path := range matches {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			if strings.EqualFold(desktopEntryName(string(data)), name) {
+				return path, nil
+			}
+		}
+	}
+	return "", ErrAppNotFound
+}
+
+// applicationDirs returns the XDG application directories, most specific first.
+func applicationDirs() []string {
+	var dirs []string
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		if home, err := os.UserHomeDir(); err == nil {
+			dataHome = filepath.Join(home, ".local", "share")
+		}
+	}
+	if dataHome != "" {
+		dirs = append(dirs, filepath.Join(dataHome, "applications"))
+	}
+
+	dataDirs := os.Getenv("XDG_DATA_DIRS")
+	if dataDirs == "" {
+		dataDirs = "/usr/local/share:/usr/share"
+	}
+	for _, dir := range filepath.SplitList(dataDirs) {
+		if dir != "" {
+			dirs = append(dirs, filepath.Join(dir, "applications"))
+		}
+	}
+	return dirs
+}
+
+// desktopEntryName returns the Name= value of a .desktop file's
+// [Desktop Entry] group.
+func desktopEntryName(data string) string {
+	inEntry := false
+	for _, line := range strings.Split(data, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[") {
+			inEntry = line == "[Desktop Entry]"
+			continue
+		}
+		if inEntry {
+			if value, ok := strings.CutPrefix(line, "Name="); ok {
+				return value
+			}
+		}
+	}
+	return ""
+}
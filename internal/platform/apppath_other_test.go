@@ -0,0 +1,67 @@
+//go:build !darwin && !windows
+
+package platform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveAppPathDesktopFiles(t *testing.T) {
+	home, shared := t.TempDir(), t.TempDir()
+	t.Setenv("XDG_DATA_HOME", home)
+	t.Setenv("XDG_DATA_DIRS", shared)
+
+	write := func(dir, file, content string) string {
+		path := filepath.Join(dir, "applications", file)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	calculator := write(shared, "org.gnome.Calculator.desktop", "[Desktop Entry]\nName=Calculator\nExec=gnome-calculator\n")
+	write(shared, "firefox.desktop", "[Desktop Entry]\nName=Firefox Web Browser\n")
+	userFirefox := write(home, "firefox.desktop", "[Desktop Entry]\nName=Firefox (user)\n")
+	write(shared, "other.desktop", "[Desktop Action new]\nName=Terminal\n")
+
+	tests := []struct {
+		name, input, want string
+	}{
+		{"by file name, user directory first", "firefox", userFirefox},
+		{"by Name= entry", "Calculator", calculator},
+		{"by Name= entry, case-insensitive", "calculator", calculator},
+		{"by full file name", "org.gnome.Calculator.desktop", calculator},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ResolveAppPath(tt.input)
+			if err != nil || got != tt.want {
+				t.Errorf("ResolveAppPath(%q) = (%q, %v), want %q", tt.input, got, err, tt.want)
+			}
+		})
+	}
+
+	// Names outside the [Desktop Entry] group don't count
+	if _, err := ResolveAppPath("Terminal"); !errors.Is(err, ErrAppNotFound) {
+		t.Errorf("ResolveAppPath(Terminal) err = %v, want ErrAppNotFound", err)
+	}
+}
+
+func TestDesktopEntryName(t *testing.T) {
+	tests := []struct{ data, want string }{
+		{"[Desktop Entry]\nType=Application\nName=Files\n", "Files"},
+		{"[Desktop Entry]\r\nName=Files\r\n", "Files"},
+		{"[Desktop Action window]\nName=New Window\n[Desktop Entry]\nName=Editor\n", "Editor"},
+		{"Name=Orphan\n", ""},
+	}
+	for _, tt := range tests {
+		if got := desktopEntryName(tt.data); got != tt.want {
+			t.Errorf("desktopEntryName(%q) = %q, want %q", tt.data, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package platform
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestAppCandidates(t *testing.T) {
+	tests := []struct {
+		name, ext string
+		want      []string
+	}{
+		{"Safari", ".app", []string{"Safari", "Safari.app"}},
+		{"Safari.app", ".app", []string{"Safari.app"}},
+		{"Safari.APP", ".app", []string{"Safari.APP"}},
+		{"chrome", ".exe", []string{"chrome", "chrome.exe"}},
+		{"org.gnome.Calculator", ".desktop", []string{"org.gnome.Calculator", "org.gnome.Calculator.desktop"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := appCandidates(tt.name, tt.ext); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("appCandidates(%q, %q) = %v, want %v", tt.name, tt.ext, got, tt.want)
+			}
+		})
+	}
+}
+
+// makeTree creates the given files (and their parent directories) under root.
+func makeTree(t *testing.T, root string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(root, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestFindInDirs(t *testing.T) {
+	first, second := t.TempDir(), t.TempDir()
+	makeTree(t, first, "Tools/Editor.lnk", "Deep/Nested/Hidden.lnk")
+	makeTree(t, second, "Editor.lnk", "Browser.LNK")
+	// A match inside a bundle is not searched
+	makeTree(t, second, "Suite.app/Contents/Inner.lnk")
+
+	tests := []struct {
+		name       string
+		candidates []string
+		depth      int
+		want       string
+	}{
+		{"earlier directory wins", []string{"editor.lnk"}, 1, filepath.Join(first, "Tools", "Editor.lnk")},
+		{"top level only", []string{"editor.lnk"}, 0, filepath.Join(second, "Editor.lnk")},
+		{"case-insensitive", []string{"browser.lnk"}, 1, filepath.Join(second, "Browser.LNK")},
+		{"depth limit", []string{"hidden.lnk"}, 1, ""},
+		{"within depth", []string{"hidden.lnk"}, 2, filepath.Join(first, "Deep", "Nested", "Hidden.lnk")},
+		{"bundles are not searched", []string{"inner.lnk"}, 3, ""},
+		{"no match", []string{"missing.lnk"}, 2, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := findInDirs([]string{first, filepath.Join(first, "absent"), second}, tt.candidates, tt.depth)
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("findInDirs = (%q, %v), want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveAppPathEmptyName(t *testing.T) {
+	if _, err := ResolveAppPath("  "); !errors.Is(err, ErrAppNotFound) {
+		t.Errorf("err = %v, want ErrAppNotFound", err)
+	}
+}
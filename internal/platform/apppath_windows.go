@@ -0,0 +1,49 @@
+//go:build windows
+
+package platform
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// regDefaultValuePattern extracts the default value from "reg query /ve"
+// output, e.g. "(Default)    REG_SZ    C:\Program Files\...\chrome.exe".
+var regDefaultValuePattern = regexp.MustCompile(`REG_(?:EXPAND_)?SZ\s+(.+)`)
+
+// resolveAppPath looks up the registry App Paths entry for the executable,
+// falling back to a Start Menu shortcut.
+func resolveAppPath(name string) (string, error) {
+	for _, exe := range appCandidates(name, ".exe") {
+		if !strings.EqualFold(filepath.Ext(exe), ".exe") {
+			continue
+		}
+		for _, root := range []string{"HKCU", "HKLM"} {
+			key := root + `\SOFTWARE\Microsoft\Windows\CurrentVersion\App Paths\` + exe
+			out, err := exec.Command("reg", "query", key, "/ve").Output()
+			if err != nil {
+				continue
+			}
+			if m := regDefaultValuePattern.FindSubmatch(out); m != nil {
+				path := os.ExpandEnv(strings.Trim(strings.TrimSpace(string(m[1])), `"`))
+				if _, err := os.Stat(path); err == nil {
+					return path, nil
+				}
+			}
+		}
+	}
+
+	var dirs []string
+	for _, env := range []string{"APPDATA", "ProgramData"} {
+		if base := os.Getenv(env); base != "" {
+			dirs = append(dirs, filepath.Join(base, "Microsoft", "Windows", "Start Menu", "Programs"))
+		}
+	}
+	if path, ok := findInDirs(dirs, appCandidates(name, ".lnk"), 2); ok {
+		return path, nil
+	}
+	return "", ErrAppNotFound
+}
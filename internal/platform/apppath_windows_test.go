@@ -0,0 +1,22 @@
+//go:build windows
+
+package platform
+
+import "testing"
+
+func TestRegDefaultValuePattern(t *testing.T) {
+	tests := []struct{ out, want string }{
+		{"\r\nHKEY_LOCAL_MACHINE\\...\\chrome.exe\r\n    (Default)    REG_SZ    C:\\Program Files\\Google\\Chrome\\Application\\chrome.exe", `C:\Program Files\Google\Chrome\Application\chrome.exe`},
+		{"    (Default)    REG_EXPAND_SZ    %ProgramFiles%\\app.exe", `%ProgramFiles%\app.exe`},
+		{"ERROR: The system was unable to find the specified registry key or value.", ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if m := regDefaultValuePattern.FindStringSubmatch(tt.out); m != nil {
+			got = m[1]
+		}
+		if got != tt.want {
+			t.Errorf("default value of %q = %q, want %q", tt.out, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+// Package platform describes the operating system the agent is running on.
+package platform
+
+import (
+	"runtime"
+	"sync"
+)
+
+// Info describes the host operating system.
+type Info struct {
+	OS          string // runtime.GOOS value (e.g., "darwin")
+	DisplayName string // Human-readable OS name (e.g., "macOS")
+	Version     string // OS version (e.g., "14.5"); empty if it cannot be determined
+	Arch        string // runtime.GOARCH value (e.g., "arm64")
+}
+
+var (
+	currentOnce sync.Once
+	current     Info
+)
+
+// Current returns information about the host OS.
+// The version lookup runs once and is cached for the life of the process.
+func Current() Info {
+	currentOnce.Do(func() {
+		current = Info{
+			OS:          runtime.GOOS,
+			DisplayName: displayName(runtime.GOOS),
+			Version:     queryVersion(),
+			Arch:        runtime.GOARCH,
+		}
+	})
+	return current
+}
+
+// String returns the display name and version, e.g. "macOS 14.5".
+func (i Info) String() string {
+	if i.Version == "" {
+		return i.DisplayName
+	}
+	return i.DisplayName + " " + i.Version
+}
+
+// displayName returns the human-readable name of a GOOS value.
+func displayName(goos string) string {
+	switch goos {
+	case "darwin":
+		return "macOS"
+	case "windows":
+		return "Windows"
+	case "linux":
+		return "Linux"
+	default:
+		return goos
+	}
+}
@@ -0,0 +1,48 @@
+package platform
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestDisplayName(t *testing.T) {
+	for goos, want := range map[string]string{
+		"darwin":  "macOS",
+		"windows": "Windows",
+		"linux":   "Linux",
+		"freebsd": "freebsd",
+	} {
+		if got := displayName(goos); got != want {
+			t.Errorf("displayName(%q) = %q, want %q", goos, got, want)
+		}
+	}
+}
+
+func TestInfoString(t *testing.T) {
+	tests := []struct {
+		info Info
+		want string
+	}{
+		{Info{DisplayName: "macOS", Version: "14.5"}, "macOS 14.5"},
+		{Info{DisplayName: "Linux", Version: "Ubuntu 24.04 LTS"}, "Linux Ubuntu 24.04 LTS"},
+		{Info{DisplayName: "Windows"}, "Windows"},
+	}
+	for _, tt := range tests {
+		if got := tt.info.String(); got != tt.want {
+			t.Errorf("String() = %q, want %q", got, tt.want)
+		}
+	}
+}
+
+func TestCurrent(t *testing.T) {
+	info := Current()
+	if info.OS != runtime.GOOS || info.Arch != runtime.GOARCH {
+		t.Errorf("Current() = %+v, want %s/%s", info, runtime.GOOS, runtime.GOARCH)
+	}
+	if info.DisplayName != displayName(runtime.GOOS) {
+		t.Errorf("DisplayName = %q, want %q", info.DisplayName, displayName(runtime.GOOS))
+	}
+	if again := Current(); again != info {
+		t.Errorf("second Current() = %+v, want the cached %+v", again, info)
+	}
+}
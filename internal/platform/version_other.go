@@ -0,0 +1,34 @@
+//go:build !darwin && !windows
+
+package platform
+
+import (
+	"os"
+	"strings"
+)
+
+// queryVersion returns the distribution and version from /etc/os-release
+// (e.g., "Ubuntu 24.04 LTS"), or "" if unavailable.
+func queryVersion() string {
+	data, err := os.ReadFile("/etc/os-release")
+	if err != nil {
+		return ""
+	}
+	return parseOSRelease(string(data))
+}
+
+// parseOSRelease returns PRETTY_NAME, falling back to VERSION_ID.
+func parseOSRelease(data string) string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(data, "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok {
+			continue
+		}
+		fields[key] = strings.Trim(value, `"'`)
+	}
+	if name := fields["PRETTY_NAME"]; name != "" {
+		return name
+	}
+	return fields["VERSION_ID"]
+}
@@ -0,0 +1,24 @@
+//go:build !darwin && !windows
+
+package platform
+
+import "testing"
+
+func TestParseOSRelease(t *testing.T) {
+	tests := []struct {
+		name, data, want string
+	}{
+		{"pretty name", "NAME=\"Ubuntu\"\nVERSION_ID=\"24.04\"\nPRETTY_NAME=\"Ubuntu 24.04 LTS\"\n", "Ubuntu 24.04 LTS"},
+		{"single quotes", "PRETTY_NAME='Fedora Linux 40'\n", "Fedora Linux 40"},
+		{"version id fallback", "NAME=Alpine\nVERSION_ID=3.20.0\n", "3.20.0"},
+		{"comments and blanks", "# generated\n\nPRETTY_NAME=\"Debian GNU/Linux 12 (bookworm)\"\n", "Debian GNU/Linux 12 (bookworm)"},
+		{"empty", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseOSRelease(tt.data); got != tt.want {
+				t.Errorf("parseOSRelease = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
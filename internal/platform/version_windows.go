@@ -0,0 +1,24 @@
+//go:build windows
+
+package platform
+
+import (
+	"os/exec"
+	"regexp"
+)
+
+// windowsVersionPattern extracts the version from "ver" output, e.g.
+// "Microsoft Windows [Version 10.0.22631.3593]".
+var windowsVersionPattern = regexp.MustCompile(`\[Version ([0-9.]+)\]`)
+
+// queryVersion returns the Windows version number (e.g., "10.0.22631.3593").
+func queryVersion() string {
+	out, err := exec.Command("cmd", "/c", "ver").Output()
+	if err != nil {
+		return ""
+	}
+	if m := windowsVersionPattern.FindSubmatch(out); m != nil {
+		return string(m[1])
+	}
+	return ""
+}
@@ -0,0 +1,20 @@
+//go:build windows
+
+package platform
+
+import "testing"
+
+func TestWindowsVersionPattern(t *testing.T) {
+	tests := []struct{ out, want string }{
+		{"\r\nMicrosoft Windows [Version 10.0.22631.3593]\r\n", "10.0.22631.3593"},
+	}
+	for _, tt := range tests {
+		got := ""
+		if m := windowsVersionPattern.FindStringSubmatch(tt.out); m != nil {
+			got = m[1]
+		}
+		if got != tt.want {
+			t.Errorf("version in %q = %q, want %q", tt.out, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package safety
+
+import (
+	"sync"
+	"time"
+)
+
+// RateLimitWindow is the sliding window over which RateLimiter counts actions.
+const RateLimitWindow = time.Minute
+
+// RateLimiter limits how often each action may run per RateLimitWindow.
+// It is safe for concurrent use.
+type RateLimiter struct {
+	mu           sync.Mutex
+	defaultLimit int
+	limits       map[string]int
+	calls        map[string][]time.Time // Recent call times per action, oldest first
+}
+
+// NewRateLimiter creates a rate limiter. limits maps action names to their
+// per-window limit; actions not in limits use defaultLimit. A limit <= 0
+// means unlimited, so a limits entry of 0 exempts an action from the default.
+func NewRateLimiter(defaultLimit int, limits map[string]int) *RateLimiter {
+	copied := make(map[string]int, len(limits))
+	for action, limit := range limits {
+		copied[action] = limit
+	}
+	return &RateLimiter{
+		defaultLimit: defaultLimit,
+		limits:       copied,
+		calls:        make(map[string][]time.Time),
+	}
+}
+
+// Limit returns the per-window limit for action, and whether it is set
+// explicitly rather than by the default.
+func (r *RateLimiter) Limit(action string) (limit int, explicit bool) {
+	if limit, ok := r.limits[action]; ok {
+		return limit, true
+	}
+	return r.defaultLimit, false
+}
+
+// Allowed reports whether action may run at now under its limit. It does not
+// record the call; call Record once the action actually runs, so calls that
+// are turned away for other reasons don't use up the limit. Concurrent callers
+// that all pass Allowed before recording may overshoot the limit slightly.
+func (r *RateLimiter) Allowed(action string, now time.Time) bool {
+	limit, _ := r.Limit(action)
+	if limit <= 0 {
+		return true
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.prune(action, now)) < limit
+}
+
+// Record records a call of action at now.
+func (r *RateLimiter) Record(action string, now time.Time) {
+	if limit, _ := r.Limit(action); limit <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls[action] = append(r.prune(action, now), now)
+}
+
+// prune drops the calls of action that have left the window at now and
+// returns the remaining ones. r.mu must be held.
+func (r *RateLimiter) prune(action string, now time.Time) []time.Time {
+	recent := r.calls[action]
+	cutoff := now.Add(-RateLimitWindow)
+	i := 0
+	for i < len(recent) && !recent[i].After(cutoff) {
+		i++
+	}
+	recent = recent[i:]
+	r.calls[action] = recent
+	return recent
+}
@@ -0,0 +1,145 @@
+package safety
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRateLimiterAllowed(t *testing.T) {
+	start := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+
+	type call struct {
+		action string
+		at     time.Duration // Offset from start
+		want   bool
+	}
+	tests := []struct {
+		name         string
+		defaultLimit int
+		limits       map[string]int
+		calls        []call
+	}{
+		{
+			name:         "default limit",
+			defaultLimit: 2,
+			calls: []call{
+				{"mouse_click", 0, true},
+				{"mouse_click", time.Second, true},
+				{"mouse_click", 2 * time.Second, false},
+			},
+		},
+		{
+			name:         "window slides",
+			defaultLimit: 2,
+			calls: []call{
+				{"mouse_click", 0, true},
+				{"mouse_click", 30 * time.Second, true},
+				{"mouse_click", 59 * time.Second, false},
+				{"mouse_click", RateLimitWindow, true}, // The first call has left the window
+				{"mouse_click", RateLimitWindow + time.Second, false},
+				{"mouse_click", 91 * time.Second, true},
+			},
+		},
+		{
+			name:         "actions counted separately",
+			defaultLimit: 1,
+			calls: []call{
+				{"mouse_click", 0, true},
+				{"keyboard_type", 0, true},
+				{"mouse_click", time.Second, false},
+				{"keyboard_type", time.Second, false},
+			},
+		},
+		{
+			name:         "explicit limits override default",
+			defaultLimit: 1,
+			limits:       map[string]int{"keyboard_type": 3, "app_launch": 0},
+			calls: []call{
+				{"keyboard_type", 0, true},
+				{"keyboard_type", 1, true},
+				{"keyboard_type", 2, true},
+				{"keyboard_type", 3, false},
+				{"app_launch", 0, true}, // 0 exempts the action from the default
+				{"app_launch", 1, true},
+				{"app_launch", 2, true},
+			},
+		},
+		{
+			name: "no default limit",
+			calls: []call{
+				{"mouse_click", 0, true},
+				{"mouse_click", 0, true},
+				{"mouse_click", 0, true},
+			},
+		},
+		{
+			name:         "blocked calls are not recorded",
+			defaultLimit: 1,
+			calls: []call{
+				{"mouse_click", 0, true},
+				{"mouse_click", 50 * time.Second, false},
+				// Only the first call counts, so this one is allowed
+				{"mouse_click", RateLimitWindow + time.Second, true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewRateLimiter(tt.defaultLimit, tt.limits)
+			for i, c := range tt.calls {
+				got := r.Allowed(c.action, start.Add(c.at))
+				if got != c.want {
+					t.Errorf("call %d (%s at +%v): Allowed = %v, want %v", i, c.action, c.at, got, c.want)
+				}
+				if got {
+					r.Record(c.action, start.Add(c.at))
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiterAllowedDoesNotRecord(t *testing.T) {
+	now := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	r := NewRateLimiter(1, nil)
+
+	for i := 0; i < 3; i++ {
+		if !r.Allowed("mouse_click", now) {
+			t.Fatalf("check %d: Allowed = false before any call was recorded", i)
+		}
+	}
+	r.Record("mouse_click", now)
+	if r.Allowed("mouse_click", now) {
+		t.Error("Allowed = true after the limit's one call was recorded")
+	}
+}
+
+func TestRateLimiterLimit(t *testing.T) {
+	r := NewRateLimiter(5, map[string]int{"keyboard_type": 10, "app_launch": 0})
+
+	tests := []struct {
+		action       string
+		wantLimit    int
+		wantExplicit bool
+	}{
+		{"mouse_click", 5, false},
+		{"keyboard_type", 10, true},
+		{"app_launch", 0, true},
+	}
+	for _, tt := range tests {
+		limit, explicit := r.Limit(tt.action)
+		if limit != tt.wantLimit || explicit != tt.wantExplicit {
+			t.Errorf("Limit(%q) = %d, %v; want %d, %v", tt.action, limit, explicit, tt.wantLimit, tt.wantExplicit)
+		}
+	}
+}
+
+func TestNewRateLimiterCopiesLimits(t *testing.T) {
+	limits := map[string]int{"mouse_click": 1}
+	r := NewRateLimiter(0, limits)
+	limits["mouse_click"] = 100
+
+	if limit, _ := r.Limit("mouse_click"); limit != 1 {
+		t.Errorf("limit changed to %d after mutating the caller's map", limit)
+	}
+}
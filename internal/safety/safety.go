@@ -0,0 +1,64 @@
+// Package safety provides guardrails for unattended automation.
+package safety
+
+import (
+	"image"
+	"math"
+	"time"
+)
+
+// OperatingHours is a daily window, in local time, during which actions are allowed.
+// Start and End are offsets from midnight. A window whose End is before its
+// Start spans midnight (e.g., 22:00-06:00).
+type OperatingHours struct {
+	Start time.Duration
+	End   time.Duration
+}
+
+// Contains reports whether t falls within the window.
+func (h OperatingHours) Contains(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+
+	if h.Start <= h.End {
+		return offset >= h.Start && offset < h.End
+	}
+	// Overnight window
+	return offset >= h.Start || offset < h.End
+}
+
+// lockScreenMaxStdDev is the luminance standard deviation below which a
+// capture is considered near-uniform (blank, locked, or a dimmed screensaver).
+const lockScreenMaxStdDev = 4.0
+
+// lockScreenSamples is the number of grid samples per axis.
+const lockScreenSamples = 32
+
+// IsLockScreen reports whether img looks like a lock screen or screensaver,
+// detected as a near-uniform capture. It samples a grid of pixels, so it is
+// cheap even on large captures.
+func IsLockScreen(img image.Image) bool {
+	b := img.Bounds()
+	if b.Empty() {
+		return true
+	}
+
+	var sum, sumSq float64
+	n := 0
+	for i := 0; i < lockScreenSamples; i++ {
+		y := b.Min.Y + i*b.Dy()/lockScreenSamples
+		for j := 0; j < lockScreenSamples; j++ {
+			x := b.Min.X + j*b.Dx()/lockScreenSamples
+			r, g, bl, _ := img.At(x, y).RGBA()
+			// Rec. 601 luma, scaled to 0-255
+			l := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+			sum += l
+			sumSq += l * l
+			n++
+		}
+	}
+
+	mean := sum / float64(n)
+	variance := sumSq/float64(n) - mean*mean
+	return math.Sqrt(math.Max(variance, 0)) < lockScreenMaxStdDev
+}
@@ -0,0 +1,67 @@
+package safety
+
+import (
+	"image"
+	"testing"
+	"time"
+)
+
+func TestOperatingHoursContains(t *testing.T) {
+	day := OperatingHours{Start: 9 * time.Hour, End: 17 * time.Hour}
+	night := OperatingHours{Start: 22 * time.Hour, End: 6 * time.Hour}
+	at := func(h, m int) time.Time { return time.Date(2026, 3, 14, h, m, 0, 0, time.Local) }
+
+	tests := []struct {
+		name  string
+		hours OperatingHours
+		t     time.Time
+		want  bool
+	}{
+		{"day window start is inclusive", day, at(9, 0), true},
+		{"inside day window", day, at(12, 30), true},
+		{"day window end is exclusive", day, at(17, 0), false},
+		{"before day window", day, at(8, 59), false},
+		{"overnight before midnight", night, at(23, 0), true},
+		{"overnight after midnight", night, at(5, 59), true},
+		{"outside overnight window", night, at(12, 0), false},
+		{"empty window", OperatingHours{}, at(0, 0), false},
+		{"whole day", OperatingHours{End: 24 * time.Hour}, at(23, 59), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hours.Contains(tt.t); got != tt.want {
+				t.Errorf("Contains(%s) = %v, want %v", tt.t.Format("15:04"), got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLockScreen(t *testing.T) {
+	noisy := image.NewGray(image.Rect(0, 0, 640, 480))
+	for i := range noisy.Pix {
+		noisy.Pix[i] = byte(i * 31)
+	}
+	// A dark screensaver with faint, low-contrast detail
+	dim := image.NewGray(image.Rect(0, 0, 640, 480))
+	for i := range dim.Pix {
+		dim.Pix[i] = byte(10 + i%3)
+	}
+
+	tests := []struct {
+		name string
+		img  image.Image
+		want bool
+	}{
+		{"black", image.NewGray(image.Rect(0, 0, 1920, 1080)), true},
+		{"dim screensaver", dim, true},
+		{"desktop content", noisy, false},
+		{"empty capture", image.NewGray(image.Rectangle{}), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsLockScreen(tt.img); got != tt.want {
+				t.Errorf("IsLockScreen = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
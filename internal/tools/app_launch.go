@@ -26,10 +26,10 @@ Examples:
 - "Safari" or "Google Chrome" → Opens browser
 - "Terminal" or "cmd" → Opens terminal
 
-On macOS: Uses 'open -a' command
-On Windows: Uses 'start' command or direct execution
+On macOS: Opens the resolved .app bundle, falling back to 'open -a'
+On Windows: Starts the resolved executable or Start Menu shortcut, falling back to 'start' or direct execution
 
-Returns success with the launched app name, or error if app not found.`
+Returns success with the launched app name (on macOS also its pid, once it is brought to the front), or error if app not found.`
 }
 
 func (t *AppLaunchTool) Parameters() map[string]ParameterSpec {
@@ -70,3 +70,39 @@ func (t *AppLaunchTool) Execute(ctx context.Context, argsJSON string) (string, e
 func (t *AppLaunchTool) Run(ctx context.Context, input string) (string, error) {
 	return t.Execute(ctx, input)
 }
+
+// launchResult describes a successful launch for the model.
+type launchResult struct {
+	Launched string // Name or command the app was launched by
+	Path     string // Resolved bundle or executable, if known
+	URI      string // URI the app was launched through (Windows)
+	Platform string
+	Waited   bool
+
+	// Set on macOS once the app is brought to the front. Activation is
+	// skipped when the call waited for the app to exit.
+	PID       int   // 0 if Launch Services never reported it
+	Activated *bool // nil if activation was not attempted
+}
+
+// response builds the tool's success response, leaving out unknown fields.
+func (r launchResult) response() string {
+	data := map[string]interface{}{
+		"launched": r.Launched,
+		"platform": r.Platform,
+		"waited":   r.Waited,
+	}
+	if r.Path != "" {
+		data["path"] = r.Path
+	}
+	if r.URI != "" {
+		data["uri"] = r.URI
+	}
+	if r.PID > 0 {
+		data["pid"] = r.PID
+	}
+	if r.Activated != nil {
+		data["activated"] = *r.Activated
+	}
+	return SuccessResponse(data)
+}
@@ -5,8 +5,13 @@ package tools
 import (
 	"context"
 	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/anxuanzi/cua/internal/platform"
 )
 
 // launchApp launches an application on macOS using the 'open' command.
@@ -67,6 +72,21 @@ func launchApp(ctx context.Context, appName string, wait bool) (string, error) {
 		variations = append([]string{mapped}, variations...)
 	}
 
+	// Open the resolved bundle directly when we can find it
+	if appPath, err := platform.ResolveAppPath(variations[0]); err == nil {
+		args := []string{appPath}
+		if wait {
+			args = []string{"-W", appPath}
+		}
+		if err := exec.CommandContext(ctx, "open", args...).Run(); err == nil {
+			result := launchResult{Launched: variations[0], Path: appPath, Platform: "darwin", Waited: wait}
+			if !wait {
+				activate(ctx, &result, strings.TrimSuffix(filepath.Base(appPath), ".app"))
+			}
+			return result.response(), nil
+		}
+	}
+
 	var lastErr error
 	for _, name := range variations {
 		args := []string{"-a", name}
@@ -77,39 +97,13 @@ func launchApp(ctx context.Context, appName string, wait bool) (string, error) {
 		cmd := exec.CommandContext(ctx, "open", args...)
 		err := cmd.Run()
 		if err == nil {
-			// Give the app a moment to launch
-			time.Sleep(500 * time.Millisecond)
-
-			return SuccessResponse(map[string]interface{}{
-				"launched": name,
-				"platform": "darwin",
-				"waited":   wait,
-			}), nil
-		}
-		lastErr = err
-	}
-
-	// If all variations failed, try mdfind to find the app
-	mdfindCmd := exec.CommandContext(ctx, "mdfind", "kMDItemKind == 'Application' && kMDItemDisplayName == '"+appName+"'")
-	output, err := mdfindCmd.Output()
-	if err == nil && len(output) > 0 {
-		// Found the app path, try to open it directly
-		appPath := strings.TrimSpace(strings.Split(string(output), "\n")[0])
-		if appPath != "" {
-			cmd := exec.CommandContext(ctx, "open", appPath)
-			if wait {
-				cmd = exec.CommandContext(ctx, "open", "-W", appPath)
-			}
-			if err := cmd.Run(); err == nil {
-				time.Sleep(500 * time.Millisecond)
-				return SuccessResponse(map[string]interface{}{
-					"launched": appName,
-					"path":     appPath,
-					"platform": "darwin",
-					"waited":   wait,
-				}), nil
+			result := launchResult{Launched: name, Platform: "darwin", Waited: wait}
+			if !wait {
+				activate(ctx, &result, strings.TrimSuffix(name, ".app"))
 			}
+			return result.response(), nil
 		}
+		lastErr = err
 	}
 
 	return ErrorResponse(
@@ -117,3 +111,42 @@ func launchApp(ctx context.Context, appName string, wait bool) (string, error) {
 		"Check if the application is installed. Error: "+lastErr.Error(),
 	), nil
 }
+
+// appActivateTimeout bounds how long to wait for a launched app to register.
+const appActivateTimeout = 3 * time.Second
+
+// lsappinfoPIDPattern extracts the PID from `lsappinfo info -only pid` output,
+// e.g. "pid"=1234.
+var lsappinfoPIDPattern = regexp.MustCompile(`"pid"\s*=\s*(\d+)`)
+
+// activate waits for the launched app to register, brings it to the front,
+// and records its PID and whether it was activated in result. 'open' returns
+// as soon as the launch is requested, so this makes the app's state
+// deterministic before the model's next screenshot.
+func activate(ctx context.Context, result *launchResult, appName string) {
+	result.PID = waitForAppPID(ctx, appName)
+
+	script := `tell application "` + strings.ReplaceAll(appName, `"`, `\"`) + `" to activate`
+	activated := exec.CommandContext(ctx, "osascript", "-e", script).Run() == nil
+	result.Activated = &activated
+}
+
+// waitForAppPID polls Launch Services for the app's PID until it appears or
+// appActivateTimeout passes. It returns 0 if the PID is unknown.
+func waitForAppPID(ctx context.Context, appName string) int {
+	deadline := time.Now().Add(appActivateTimeout)
+	for {
+		out, err := exec.CommandContext(ctx, "lsappinfo", "info", "-only", "pid", appName).Output()
+		if err == nil {
+			if m := lsappinfoPIDPattern.FindSubmatch(out); m != nil {
+				if pid, err := strconv.Atoi(string(m[1])); err == nil && pid > 0 {
+					return pid
+				}
+			}
+		}
+		if time.Now().After(deadline) || ctx.Err() != nil {
+			return 0
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
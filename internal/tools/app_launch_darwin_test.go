@@ -0,0 +1,54 @@
+//go:build darwin
+
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// requireGUI skips the test unless it runs in a logged-in macOS GUI session,
+// where apps can be launched and activated.
+func requireGUI(t *testing.T) {
+	t.Helper()
+	if testing.Short() {
+		t.Skip("launches a real app")
+	}
+	out, err := exec.Command("launchctl", "managername").Output()
+	if err != nil || strings.TrimSpace(string(out)) != "Aqua" {
+		t.Skip("no GUI session")
+	}
+}
+
+func TestLaunchAppActivates(t *testing.T) {
+	requireGUI(t)
+	t.Cleanup(func() {
+		exec.Command("osascript", "-e", `tell application "Calculator" to quit`).Run()
+	})
+
+	out, err := launchApp(context.Background(), "calculator", false)
+	if err != nil {
+		t.Fatalf("launchApp: %v", err)
+	}
+	var result struct {
+		Success   bool   `json:"success"`
+		Launched  string `json:"launched"`
+		PID       int    `json:"pid"`
+		Activated bool   `json:"activated"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("response is not JSON: %v\n%s", err, out)
+	}
+	if !result.Success || result.Launched != "Calculator" {
+		t.Fatalf("launch failed: %s", out)
+	}
+	if result.PID <= 0 {
+		t.Errorf("pid = %d, want the launched app's PID", result.PID)
+	}
+	if !result.Activated {
+		t.Errorf("app was not activated: %s", out)
+	}
+}
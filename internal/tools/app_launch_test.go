@@ -0,0 +1,54 @@
+package tools
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLaunchResultResponse(t *testing.T) {
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		result launchResult
+		want   map[string]interface{}
+	}{
+		{
+			name:   "minimal",
+			result: launchResult{Launched: "calc", Platform: "windows"},
+			want:   map[string]interface{}{"success": true, "launched": "calc", "platform": "windows", "waited": false},
+		},
+		{
+			name:   "path and uri",
+			result: launchResult{Launched: "Settings", Path: `C:\Windows\settings.exe`, URI: "ms-settings:", Platform: "windows", Waited: true},
+			want: map[string]interface{}{
+				"success": true, "launched": "Settings", "path": `C:\Windows\settings.exe`, "uri": "ms-settings:",
+				"platform": "windows", "waited": true,
+			},
+		},
+		{
+			name:   "activated with pid",
+			result: launchResult{Launched: "Safari", Path: "/Applications/Safari.app", Platform: "darwin", PID: 4242, Activated: &yes},
+			want: map[string]interface{}{
+				"success": true, "launched": "Safari", "path": "/Applications/Safari.app", "platform": "darwin",
+				"waited": false, "pid": float64(4242), "activated": true,
+			},
+		},
+		{
+			name:   "activation failed without pid",
+			result: launchResult{Launched: "Notes", Platform: "darwin", Activated: &no},
+			want:   map[string]interface{}{"success": true, "launched": "Notes", "platform": "darwin", "waited": false, "activated": false},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.result.response()), &got); err != nil {
+				t.Fatalf("response is not JSON: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("response = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"os/exec"
 	"strings"
 	"time"
+
+	"github.com/anxuanzi/cua/internal/platform"
 )
 
 // launchApp launches an application on Windows.
@@ -71,12 +73,23 @@ func launchApp(ctx context.Context, appName string, wait bool) (string, error) {
 		err := cmd.Run()
 		if err == nil {
 			time.Sleep(500 * time.Millisecond)
-			return SuccessResponse(map[string]interface{}{
-				"launched": appName,
-				"uri":      cmdName,
-				"platform": "windows",
-				"waited":   wait,
-			}), nil
+			return launchResult{Launched: appName, URI: cmdName, Platform: "windows", Waited: wait}.response(), nil
+		}
+	}
+
+	// Launch the resolved executable or shortcut directly when we can find it
+	for _, name := range []string{cmdName, appName} {
+		appPath, err := platform.ResolveAppPath(name)
+		if err != nil {
+			continue
+		}
+		args := []string{"/c", "start", "", appPath}
+		if wait {
+			args = []string{"/c", "start", "/wait", "", appPath}
+		}
+		if err := exec.CommandContext(ctx, "cmd", args...).Run(); err == nil {
+			time.Sleep(500 * time.Millisecond)
+			return launchResult{Launched: appName, Path: appPath, Platform: "windows", Waited: wait}.response(), nil
 		}
 	}
 
@@ -91,11 +104,7 @@ func launchApp(ctx context.Context, appName string, wait bool) (string, error) {
 	err := cmd.Run()
 	if err == nil {
 		time.Sleep(500 * time.Millisecond)
-		return SuccessResponse(map[string]interface{}{
-			"launched": cmdName,
-			"platform": "windows",
-			"waited":   wait,
-		}), nil
+		return launchResult{Launched: cmdName, Platform: "windows", Waited: wait}.response(), nil
 	}
 
 	// Try direct execution (for apps in PATH)
@@ -109,11 +118,7 @@ func launchApp(ctx context.Context, appName string, wait bool) (string, error) {
 			cmd.Wait()
 		}
 		time.Sleep(500 * time.Millisecond)
-		return SuccessResponse(map[string]interface{}{
-			"launched": cmdName,
-			"platform": "windows",
-			"waited":   wait,
-		}), nil
+		return launchResult{Launched: cmdName, Platform: "windows", Waited: wait}.response(), nil
 	}
 
 	return ErrorResponse(
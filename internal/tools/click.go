@@ -11,13 +11,13 @@ import (
 // ClickTool performs mouse clicks at normalized coordinates (0-1000 scale).
 type ClickTool struct {
 	BaseTool
-	// ScreenIndex specifies which screen to use (default: 0 = primary).
-	ScreenIndex int
+	// State holds settings shared with the agent's other tools, such as the screen.
+	State *State
 }
 
 // NewClickTool creates a new click tool.
 func NewClickTool() *ClickTool {
-	return &ClickTool{ScreenIndex: 0}
+	return &ClickTool{State: NewState()}
 }
 
 func (t *ClickTool) Name() string {
@@ -85,20 +85,17 @@ func (t *ClickTool) Execute(ctx context.Context, argsJSON string) (string, error
 	}
 
 	// Get screen info
-	screenIndex := args.ScreenIndex
-	if screenIndex == 0 && t.ScreenIndex != 0 {
-		screenIndex = t.ScreenIndex
-	}
+	screenIndex := t.State.resolveScreen(args.ScreenIndex)
 	screen := coords.GetScreen(screenIndex)
 
 	// Convert normalized coordinates (0-1000) to absolute screen coordinates
-	// Formula: screen_coord = (normalized / 1000) * screen_dimension
+	// Formula: screen_coord = (normalized / 1000) * capture_area_dimension
 	// Standard mapping: 0=left/top, 1000=right/bottom (matches TuriX-CUA)
-	screenX := screen.X + int(float64(args.X)/1000.0*float64(screen.Width))
-	screenY := screen.Y + int(float64(args.Y)/1000.0*float64(screen.Height))
+	screenX, screenY := t.State.toScreen(args.X, args.Y, screen)
 
 	// Move to position with human-like timing
 	robotgo.Move(screenX, screenY)
+	recordLastAction(ctx, t.State, screenX, screenY)
 
 	// Human-like delay after moving (150-200ms feels natural)
 	time.Sleep(150 * time.Millisecond)
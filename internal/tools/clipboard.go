@@ -0,0 +1,59 @@
+package tools
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/go-vgo/robotgo"
+)
+
+// readClipboard and writeClipboard access the system clipboard.
+// They are variables so clipboard round-trips can be driven by a fake backend.
+var (
+	readClipboard  = robotgo.ReadAll
+	writeClipboard = robotgo.WriteAll
+)
+
+// shortcutModifier returns the modifier for standard editing shortcuts
+// (copy, paste, select all): cmd on macOS, ctrl elsewhere.
+func shortcutModifier() string {
+	if runtime.GOOS == "darwin" {
+		return "cmd"
+	}
+	return "ctrl"
+}
+
+// readFocusedField returns the text of the focused input field by selecting
+// all of it and copying it to the clipboard. The previous clipboard contents
+// are restored and the selection is collapsed to the end of the text.
+func readFocusedField() (string, error) {
+	previous, _ := readClipboard()
+	defer func() { _ = writeClipboard(previous) }()
+
+	// Clear the clipboard so a failed copy isn't mistaken for the field text
+	if err := writeClipboard(""); err != nil {
+		return "", fmt.Errorf("failed to write clipboard: %w", err)
+	}
+
+	modifier := []string{shortcutModifier()}
+	keyTap("a", modifier)
+	keySleep(100 * time.Millisecond)
+	keyTap("c", modifier)
+	keySleep(200 * time.Millisecond)
+
+	text, err := readClipboard()
+	// Deselect so further typing doesn't replace the field contents
+	keyTap("right", nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to read clipboard: %w", err)
+	}
+	return text, nil
+}
+
+// normalizeWhitespace collapses runs of whitespace to single spaces and trims
+// the ends, so line-ending and spacing differences don't count as mismatches.
+func normalizeWhitespace(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
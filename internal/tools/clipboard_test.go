@@ -0,0 +1,103 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+// fakeClipboard replaces the clipboard with an in-memory one holding initial,
+// and the keyboard with one where select-all + copy puts field on the clipboard.
+func fakeClipboard(t *testing.T, initial, field string) *string {
+	t.Helper()
+	clipboard := initial
+	origRead, origWrite := readClipboard, writeClipboard
+	t.Cleanup(func() { readClipboard, writeClipboard = origRead, origWrite })
+	readClipboard = func() (string, error) { return clipboard, nil }
+	writeClipboard = func(text string) error {
+		clipboard = text
+		return nil
+	}
+
+	fakeKeyboard(t)
+	keyTap = func(key string, modifiers []string) {
+		if key == "c" && len(modifiers) > 0 {
+			clipboard = field
+		}
+	}
+	return &clipboard
+}
+
+func TestNormalizeWhitespace(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"hello world", "hello world"},
+		{"  hello \t world\n", "hello world"},
+		{"line one\r\nline two", "line one line two"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := normalizeWhitespace(tt.in); got != tt.want {
+			t.Errorf("normalizeWhitespace(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTypeToolVerify(t *testing.T) {
+	tests := []struct {
+		name         string
+		field        string
+		wantVerified bool
+	}{
+		{"matching field", "hello  world\n", true},
+		{"dropped characters", "hllo wrld", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeTyping(t)
+			clipboard := fakeClipboard(t, "user clipboard", tt.field)
+
+			out, err := NewTypeTool().Execute(context.Background(), `{"text":"hello world","verify":true}`)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			var result map[string]any
+			if err := json.Unmarshal([]byte(out), &result); err != nil {
+				t.Fatalf("result %s: %v", out, err)
+			}
+
+			if result["verified"] != tt.wantVerified {
+				t.Errorf("verified = %v, want %v", result["verified"], tt.wantVerified)
+			}
+			if tt.wantVerified {
+				if _, ok := result["field_text"]; ok {
+					t.Error("field_text reported for a matching field")
+				}
+			} else if result["field_text"] != tt.field || result["warning"] == nil {
+				t.Errorf("mismatch reported as field_text=%v warning=%v", result["field_text"], result["warning"])
+			}
+			if *clipboard != "user clipboard" {
+				t.Errorf("clipboard = %q, want it restored", *clipboard)
+			}
+		})
+	}
+}
+
+func TestPasteTextRestoresClipboard(t *testing.T) {
+	clipboard := fakeClipboard(t, "user clipboard", "")
+	var pasted string
+	keyTap = func(key string, modifiers []string) {
+		if key == "v" && len(modifiers) > 0 {
+			pasted = *clipboard
+		}
+	}
+
+	if err := pasteText(context.Background(), "pasted text"); err != nil {
+		t.Fatalf("pasteText: %v", err)
+	}
+	if pasted != "pasted text" {
+		t.Errorf("clipboard at paste time = %q, want the text", pasted)
+	}
+	if *clipboard != "user clipboard" {
+		t.Errorf("clipboard = %q, want it restored", *clipboard)
+	}
+}
@@ -11,13 +11,13 @@ import (
 // DragTool performs mouse drag operations using normalized coordinates (0-1000 scale).
 type DragTool struct {
 	BaseTool
-	// ScreenIndex specifies which screen to use (default: 0 = primary).
-	ScreenIndex int
+	// State holds settings shared with the agent's other tools, such as the screen.
+	State *State
 }
 
 // NewDragTool creates a new drag tool.
 func NewDragTool() *DragTool {
-	return &DragTool{ScreenIndex: 0}
+	return &DragTool{State: NewState()}
 }
 
 func (t *DragTool) Name() string {
@@ -95,18 +95,13 @@ func (t *DragTool) Execute(ctx context.Context, argsJSON string) (string, error)
 	}
 
 	// Get screen info
-	screenIndex := args.ScreenIndex
-	if screenIndex == 0 && t.ScreenIndex != 0 {
-		screenIndex = t.ScreenIndex
-	}
+	screenIndex := t.State.resolveScreen(args.ScreenIndex)
 	screen := coords.GetScreen(screenIndex)
 
 	// Convert normalized coordinates (0-1000) to absolute screen coordinates
 	// Standard mapping: 0=left/top, 1000=right/bottom (matches TuriX-CUA)
-	startScreenX := screen.X + int(float64(args.StartX)/1000.0*float64(screen.Width))
-	startScreenY := screen.Y + int(float64(args.StartY)/1000.0*float64(screen.Height))
-	endScreenX := screen.X + int(float64(args.EndX)/1000.0*float64(screen.Width))
-	endScreenY := screen.Y + int(float64(args.EndY)/1000.0*float64(screen.Height))
+	startScreenX, startScreenY := t.State.toScreen(args.StartX, args.StartY, screen)
+	endScreenX, endScreenY := t.State.toScreen(args.EndX, args.EndY, screen)
 
 	// Perform drag: move to start, press, move to end, release
 	robotgo.Move(startScreenX, startScreenY)
@@ -126,6 +121,7 @@ func (t *DragTool) Execute(ctx context.Context, argsJSON string) (string, error)
 
 	time.Sleep(50 * time.Millisecond)
 	robotgo.Toggle(args.Button, "up")
+	recordLastAction(ctx, t.State, endScreenX, endScreenY)
 
 	return SuccessResponse(map[string]interface{}{
 		"dragged_from_screen":    map[string]int{"x": startScreenX, "y": startScreenY},
@@ -0,0 +1,23 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"image"
+)
+
+// hashFrame hashes a frame's pixels together with the screen and region it
+// shows, so the same pixels from a different crop are not treated as a repeat.
+func hashFrame(img *image.RGBA, screenIndex int, region image.Rectangle) [sha256.Size]byte {
+	h := sha256.New()
+	var meta [5 * 8]byte
+	for i, v := range []int{screenIndex, region.Min.X, region.Min.Y, region.Max.X, region.Max.Y} {
+		binary.LittleEndian.PutUint64(meta[i*8:], uint64(v))
+	}
+	h.Write(meta[:])
+	h.Write(img.Pix)
+
+	var sum [sha256.Size]byte
+	copy(sum[:], h.Sum(nil))
+	return sum
+}
@@ -0,0 +1,58 @@
+package tools
+
+import "context"
+
+// HelpToolName is the name of the tool the model uses to ask for human help.
+const HelpToolName = "request_help"
+
+// HelpTool lets the model stop a run and ask a human for help, e.g. for a
+// CAPTCHA, a login, or an ambiguous instruction. The agent ends the run after
+// the call; the tool itself only acknowledges the request.
+type HelpTool struct {
+	BaseTool
+}
+
+// NewHelpTool creates a new help tool.
+func NewHelpTool() *HelpTool {
+	return &HelpTool{}
+}
+
+func (t *HelpTool) Name() string {
+	return HelpToolName
+}
+
+func (t *HelpTool) Description() string {
+	return `Stop the task and ask a human for help. Use this only when you cannot continue on your own, e.g. a CAPTCHA, a login that needs credentials you don't have, or an instruction that is ambiguous. The task ends after this call.`
+}
+
+func (t *HelpTool) Parameters() map[string]ParameterSpec {
+	return map[string]ParameterSpec{
+		"reason": {
+			Type:        "string",
+			Description: "What you need help with and why you cannot continue",
+			Required:    true,
+		},
+	}
+}
+
+func (t *HelpTool) Execute(ctx context.Context, argsJSON string) (string, error) {
+	var args struct {
+		Reason string `json:"reason"`
+	}
+	if err := ParseArgs(argsJSON, &args); err != nil {
+		return ErrorResponse("invalid arguments: "+err.Error(), "Provide the reason you need help"), nil
+	}
+	if args.Reason == "" {
+		return ErrorResponse("reason cannot be empty", "Describe what you need help with"), nil
+	}
+
+	return SuccessResponse(map[string]interface{}{
+		"reason": args.Reason,
+		"note":   "Help requested. The task will stop so a human can take over.",
+	}), nil
+}
+
+// Run implements the interfaces.Tool Run method by delegating to Execute.
+func (t *HelpTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
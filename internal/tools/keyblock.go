@@ -0,0 +1,62 @@
+package tools
+
+import (
+	"errors"
+	"sort"
+	"strings"
+)
+
+// ErrDangerousKeystroke is reported when keyboard_press is asked to press a
+// blocked key combination that was not approved.
+var ErrDangerousKeystroke = errors.New("dangerous keystroke blocked")
+
+// DefaultDangerousKeyCombos lists key combinations that can destroy work or
+// end the session: quitting apps, closing windows, force-quit dialogs,
+// logging out, and clearing browser history.
+var DefaultDangerousKeyCombos = []string{
+	"cmd+q",
+	"cmd+shift+q",
+	"cmd+ctrl+q",
+	"cmd+alt+escape",
+	"cmd+shift+delete",
+	"alt+f4",
+	"ctrl+alt+delete",
+	"ctrl+shift+delete",
+}
+
+// KeyConfirmFunc approves a blocked key combination. It receives the
+// normalized combo (e.g., "cmd+q") and returns true to allow the press.
+type KeyConfirmFunc func(combo string) bool
+
+// NormalizeKeyCombo converts a key combination to a canonical form so that
+// aliases and modifier order do not matter: "Shift+Command+Q" and
+// "cmd+shift+q" both normalize to "cmd+shift+q".
+func NormalizeKeyCombo(combo string) string {
+	parts := strings.Split(strings.ToLower(combo), "+")
+	key := normalizeKeyName(parts[len(parts)-1])
+
+	seen := make(map[string]bool)
+	modifiers := make([]string, 0, len(parts)-1)
+	for _, part := range parts[:len(parts)-1] {
+		mod := normalizeModifier(part)
+		if mod == "" || seen[mod] {
+			continue
+		}
+		seen[mod] = true
+		modifiers = append(modifiers, mod)
+	}
+	sort.Strings(modifiers)
+
+	return strings.Join(append(modifiers, key), "+")
+}
+
+// isBlockedCombo reports whether combo matches any entry of the blocklist.
+func isBlockedCombo(combo string, blocked []string) bool {
+	normalized := NormalizeKeyCombo(combo)
+	for _, b := range blocked {
+		if NormalizeKeyCombo(b) == normalized {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestNormalizeKeyCombo(t *testing.T) {
+	tests := []struct {
+		combo, want string
+	}{
+		{"cmd+q", "cmd+q"},
+		{"Cmd+Q", "cmd+q"},
+		{"Command+Q", "cmd+q"},
+		{"shift+cmd+q", "cmd+shift+q"},
+		{"Shift+Command+Q", "cmd+shift+q"},
+		{"option+f4", "alt+f4"},
+		{"control+alt+del", "alt+ctrl+delete"},
+		{"ctrl + shift + Delete", "ctrl+shift+delete"},
+		{"cmd+cmd+q", "cmd+q"},
+		{"win+l", "cmd+l"},
+		{"meta+escape", "cmd+escape"},
+		{"alt+esc", "alt+escape"},
+		{"hyper+q", "q"},
+		{"enter", "enter"},
+		{"Return", "enter"},
+	}
+	for _, tt := range tests {
+		if got := NormalizeKeyCombo(tt.combo); got != tt.want {
+			t.Errorf("NormalizeKeyCombo(%q) = %q, want %q", tt.combo, got, tt.want)
+		}
+	}
+}
+
+func TestIsBlockedCombo(t *testing.T) {
+	tests := []struct {
+		combo   string
+		blocked []string
+		want    bool
+	}{
+		{"cmd+q", DefaultDangerousKeyCombos, true},
+		{"Command+Q", DefaultDangerousKeyCombos, true},
+		{"shift+cmd+q", DefaultDangerousKeyCombos, true},
+		{"Alt+F4", DefaultDangerousKeyCombos, true},
+		{"delete+alt+ctrl", DefaultDangerousKeyCombos, false}, // Last part is the key
+		{"control+alt+del", DefaultDangerousKeyCombos, true},
+		{"cmd+c", DefaultDangerousKeyCombos, false},
+		{"q", DefaultDangerousKeyCombos, false},
+		{"cmd+q", nil, false},
+		{"ctrl+w", []string{"Control+W"}, true},
+	}
+	for _, tt := range tests {
+		if got := isBlockedCombo(tt.combo, tt.blocked); got != tt.want {
+			t.Errorf("isBlockedCombo(%q, %v) = %v, want %v", tt.combo, tt.blocked, got, tt.want)
+		}
+	}
+}
+
+func TestKeyPressToolRejectsBlockedCombo(t *testing.T) {
+	var asked []string
+	tests := []struct {
+		name    string
+		confirm KeyConfirmFunc
+		asked   bool
+	}{
+		{"no confirmation callback", nil, false},
+		{"confirmation declined", func(combo string) bool {
+			asked = append(asked, combo)
+			return false
+		}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asked = nil
+			tool := NewKeyPressTool()
+			tool.BlockedCombos = DefaultDangerousKeyCombos
+			tool.ConfirmBlocked = tt.confirm
+
+			out, err := tool.Execute(context.Background(), `{"key": "Shift+Command+Q"}`)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, ErrDangerousKeystroke.Error()+": cmd+shift+q") {
+				t.Errorf("got %s, want the combo rejected", out)
+			}
+			if tt.asked && (len(asked) != 1 || asked[0] != "cmd+shift+q") {
+				t.Errorf("confirm called with %v, want the normalized combo once", asked)
+			}
+		})
+	}
+}
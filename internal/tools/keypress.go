@@ -8,9 +8,31 @@ import (
 	"github.com/go-vgo/robotgo"
 )
 
+// DefaultModifierDelayMs is how long keyboard_press waits after a modifier combo
+// so launchers and dialogs (e.g., Spotlight) have time to respond.
+const DefaultModifierDelayMs = 400
+
+// keyTap, keyToggle, and keySleep are the keyboard backend of keyboard_press.
+// They are variables so the tool can be driven by a fake backend.
+var (
+	keyTap = func(key string, modifiers []string) {
+		if len(modifiers) > 0 {
+			robotgo.KeyTap(key, modifiers)
+		} else {
+			robotgo.KeyTap(key)
+		}
+	}
+	keyToggle = func(key, direction string) { robotgo.KeyToggle(key, direction) }
+	keySleep  = time.Sleep
+)
+
 // KeyPressTool presses keyboard keys or key combinations.
 type KeyPressTool struct {
 	BaseTool
+	// BlockedCombos lists key combinations that are rejected unless approved.
+	BlockedCombos []string
+	// ConfirmBlocked, if set, is asked whether a blocked combination may be pressed.
+	ConfirmBlocked KeyConfirmFunc
 }
 
 // NewKeyPressTool creates a new keypress tool.
@@ -50,13 +72,20 @@ func (t *KeyPressTool) Parameters() map[string]ParameterSpec {
 			Required:    false,
 			Default:     0,
 		},
+		"modifier_delay_ms": {
+			Type:        "integer",
+			Description: "Wait after a modifier combo in milliseconds so the app can respond (default: 400). Increase for slow apps, decrease for fast scripted input. Ignored for single keys.",
+			Required:    false,
+			Default:     DefaultModifierDelayMs,
+		},
 	}
 }
 
 func (t *KeyPressTool) Execute(ctx context.Context, argsJSON string) (string, error) {
 	var args struct {
-		Key    string `json:"key"`
-		HoldMs int    `json:"hold_ms"`
+		Key             string `json:"key"`
+		HoldMs          int    `json:"hold_ms"`
+		ModifierDelayMs *int   `json:"modifier_delay_ms"`
 	}
 
 	if err := ParseArgs(argsJSON, &args); err != nil {
@@ -67,6 +96,25 @@ func (t *KeyPressTool) Execute(ctx context.Context, argsJSON string) (string, er
 		return ErrorResponse("key cannot be empty", "Provide the key to press"), nil
 	}
 
+	modifierDelay := DefaultModifierDelayMs
+	if args.ModifierDelayMs != nil {
+		if *args.ModifierDelayMs < 0 {
+			return ErrorResponse("modifier_delay_ms cannot be negative", "Use 0 to skip the wait"), nil
+		}
+		modifierDelay = *args.ModifierDelayMs
+	}
+
+	// Refuse destructive combinations unless explicitly approved
+	if isBlockedCombo(args.Key, t.BlockedCombos) {
+		combo := NormalizeKeyCombo(args.Key)
+		if t.ConfirmBlocked == nil || !t.ConfirmBlocked(combo) {
+			return ErrorResponse(
+				ErrDangerousKeystroke.Error()+": "+combo,
+				"This key combination can discard work or end the session. Use the application's UI instead.",
+			), nil
+		}
+	}
+
 	// Parse key combination
 	parts := strings.Split(strings.ToLower(args.Key), "+")
 	key := normalizeKeyName(parts[len(parts)-1])
@@ -80,47 +128,47 @@ func (t *KeyPressTool) Execute(ctx context.Context, argsJSON string) (string, er
 	}
 
 	// Human-like delay before key press
-	time.Sleep(150 * time.Millisecond)
+	keySleep(150 * time.Millisecond)
 
 	// Press the key
 	if args.HoldMs > 0 {
 		// Hold the key - press modifiers first, then main key
 		for _, mod := range modifiers {
-			robotgo.KeyToggle(mod, "down")
-			time.Sleep(30 * time.Millisecond) // Small delay between modifier presses
+			keyToggle(mod, "down")
+			keySleep(30 * time.Millisecond) // Small delay between modifier presses
 		}
-		robotgo.KeyToggle(key, "down")
-		time.Sleep(time.Duration(args.HoldMs) * time.Millisecond)
-		robotgo.KeyToggle(key, "up")
-		time.Sleep(30 * time.Millisecond)
+		keyToggle(key, "down")
+		keySleep(time.Duration(args.HoldMs) * time.Millisecond)
+		keyToggle(key, "up")
+		keySleep(30 * time.Millisecond)
 		// Release modifiers in reverse order
 		for i := len(modifiers) - 1; i >= 0; i-- {
-			robotgo.KeyToggle(modifiers[i], "up")
-			time.Sleep(30 * time.Millisecond)
+			keyToggle(modifiers[i], "up")
+			keySleep(30 * time.Millisecond)
 		}
 	} else {
 		// Quick tap with modifiers
-		if len(modifiers) > 0 {
-			robotgo.KeyTap(key, modifiers)
-		} else {
-			robotgo.KeyTap(key)
-		}
+		keyTap(key, modifiers)
 	}
 
 	// Human-like delay after key press
 	// Longer for modifier combos (Spotlight, app launchers need time to respond)
 	if len(modifiers) > 0 {
-		time.Sleep(400 * time.Millisecond)
+		keySleep(time.Duration(modifierDelay) * time.Millisecond)
 	} else {
-		time.Sleep(100 * time.Millisecond)
+		keySleep(100 * time.Millisecond)
 	}
 
-	return SuccessResponse(map[string]interface{}{
+	result := map[string]interface{}{
 		"pressed_key": args.Key,
 		"key":         key,
 		"modifiers":   modifiers,
 		"hold_ms":     args.HoldMs,
-	}), nil
+	}
+	if len(modifiers) > 0 {
+		result["modifier_delay_ms"] = modifierDelay
+	}
+	return SuccessResponse(result), nil
 }
 
 // Run implements the interfaces.Tool Run method by delegating to Execute.
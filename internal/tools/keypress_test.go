@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeKeyboard replaces the keyboard backend with one that records key events
+// and sleeps in order instead of touching the real keyboard or waiting.
+func fakeKeyboard(t *testing.T) *[]string {
+	t.Helper()
+	var events []string
+	origTap, origToggle, origSleep := keyTap, keyToggle, keySleep
+	t.Cleanup(func() { keyTap, keyToggle, keySleep = origTap, origToggle, origSleep })
+
+	keyTap = func(key string, modifiers []string) {
+		events = append(events, "tap "+strings.Join(append(append([]string{}, modifiers...), key), "+"))
+	}
+	keyToggle = func(key, direction string) { events = append(events, key+" "+direction) }
+	keySleep = func(d time.Duration) { events = append(events, fmt.Sprintf("sleep %v", d)) }
+	return &events
+}
+
+func TestKeyPressModifierDelay(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      string
+		wantAfter string // Final sleep after the key press
+		wantDelay any    // modifier_delay_ms in the result, nil if absent
+	}{
+		{"combo uses default delay", `{"key":"cmd+space"}`, "sleep 400ms", float64(DefaultModifierDelayMs)},
+		{"combo uses configured delay", `{"key":"cmd+space","modifier_delay_ms":1200}`, "sleep 1.2s", float64(1200)},
+		{"combo with zero delay", `{"key":"ctrl+c","modifier_delay_ms":0}`, "sleep 0s", float64(0)},
+		{"single key skips modifier delay", `{"key":"enter","modifier_delay_ms":1200}`, "sleep 100ms", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := fakeKeyboard(t)
+
+			out, err := NewKeyPressTool().Execute(context.Background(), tt.args)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			var result map[string]any
+			if err := json.Unmarshal([]byte(out), &result); err != nil || result["success"] != true {
+				t.Fatalf("result = %s", out)
+			}
+
+			if len(*events) != 3 {
+				t.Fatalf("events = %v, want a sleep, a tap, and a sleep", *events)
+			}
+			if got := (*events)[2]; got != tt.wantAfter {
+				t.Errorf("after the press: %s, want %s", got, tt.wantAfter)
+			}
+			if got := result["modifier_delay_ms"]; got != tt.wantDelay {
+				t.Errorf("modifier_delay_ms = %v, want %v", got, tt.wantDelay)
+			}
+		})
+	}
+}
+
+func TestKeyPressHold(t *testing.T) {
+	events := fakeKeyboard(t)
+
+	if _, err := NewKeyPressTool().Execute(context.Background(), `{"key":"ctrl+shift+a","hold_ms":500,"modifier_delay_ms":50}`); err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	want := []string{
+		"sleep 150ms",
+		"ctrl down", "sleep 30ms",
+		"shift down", "sleep 30ms",
+		"a down", "sleep 500ms", "a up", "sleep 30ms",
+		"shift up", "sleep 30ms",
+		"ctrl up", "sleep 30ms",
+		"sleep 50ms",
+	}
+	if !reflect.DeepEqual(*events, want) {
+		t.Errorf("events = %v\nwant %v", *events, want)
+	}
+}
+
+func TestKeyPressRejectsNegativeDelay(t *testing.T) {
+	events := fakeKeyboard(t)
+
+	out, _ := NewKeyPressTool().Execute(context.Background(), `{"key":"cmd+space","modifier_delay_ms":-1}`)
+	if !strings.Contains(out, "modifier_delay_ms cannot be negative") {
+		t.Errorf("result = %s, want a negative delay error", out)
+	}
+	if len(*events) != 0 {
+		t.Errorf("keyboard used despite invalid arguments: %v", *events)
+	}
+}
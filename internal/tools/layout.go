@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultKeyboardLayout is the layout keystroke typing assumes.
+const DefaultKeyboardLayout = "us"
+
+// typeSegment and pasteSegment insert text with key events and via the
+// clipboard. They are variables so typing can be driven by a fake backend.
+var (
+	typeSegment  = typeText
+	pasteSegment = pasteText
+)
+
+// textSegment is a run of text typed either with key events or via the clipboard.
+type textSegment struct {
+	text  string
+	paste bool
+}
+
+// isUSLayout reports whether layout is empty or a US English layout.
+func isUSLayout(layout string) bool {
+	switch strings.ToLower(layout) {
+	case "", "us", "en-us", "en_us":
+		return true
+	}
+	return false
+}
+
+// layoutKeys describes how a keyboard layout's letters and digits differ from
+// a US layout.
+type layoutKeys struct {
+	moved         string // Letters on a different key than on a US layout
+	shiftedDigits bool   // Whether digits need Shift
+}
+
+// knownLayouts maps layouts to how their letters and digits differ from US.
+// On these layouts punctuation, non-ASCII characters, and the keys listed here
+// are pasted; on layouts not listed, everything but spaces is pasted.
+var knownLayouts = map[string]layoutKeys{
+	"uk":    {},
+	"gb":    {},
+	"en-gb": {},
+	"de":    {moved: "yzYZ"},                            // QWERTZ
+	"ch":    {moved: "yzYZ"},                            // QWERTZ
+	"fr":    {moved: "aqzwmAQZWM", shiftedDigits: true}, // AZERTY
+	"be":    {moved: "aqzwmAQZWM", shiftedDigits: true}, // AZERTY
+}
+
+// typesReliably reports whether r can be typed with key events on layout.
+// On known non-US layouts letters and digits on the same keys as on a US
+// layout are typed directly; on unknown layouts only spaces are.
+func typesReliably(r rune, layout string) bool {
+	if isUSLayout(layout) || r == ' ' {
+		return true
+	}
+	keys, ok := knownLayouts[strings.ReplaceAll(strings.ToLower(layout), "_", "-")]
+	switch {
+	case !ok:
+		return false
+	case r >= '0' && r <= '9':
+		return !keys.shiftedDigits
+	case (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z'):
+		return !strings.ContainsRune(keys.moved, r)
+	}
+	return false
+}
+
+// splitByLayout splits text into segments to type and segments to paste.
+func splitByLayout(text, layout string) []textSegment {
+	var segments []textSegment
+	var current strings.Builder
+	currentPaste := false
+
+	for _, r := range text {
+		paste := !typesReliably(r, layout)
+		if current.Len() > 0 && paste != currentPaste {
+			segments = append(segments, textSegment{text: current.String(), paste: currentPaste})
+			current.Reset()
+		}
+		currentPaste = paste
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		segments = append(segments, textSegment{text: current.String(), paste: currentPaste})
+	}
+	return segments
+}
+
+// pasteText inserts text through the clipboard with the platform paste
+// shortcut, restoring the previous clipboard contents afterwards.
+func pasteText(_ context.Context, text string) error {
+	previous, _ := readClipboard()
+	if err := writeClipboard(text); err != nil {
+		return fmt.Errorf("failed to write clipboard: %w", err)
+	}
+
+	keyTap("v", []string{shortcutModifier()})
+
+	// Give the target app time to read the clipboard before restoring it
+	keySleep(200 * time.Millisecond)
+	_ = writeClipboard(previous)
+	return nil
+}
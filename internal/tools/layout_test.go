@@ -0,0 +1,130 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// fakeTyping replaces the typing backend with one that records what is typed
+// ("type:...") and pasted ("paste:...") in order.
+func fakeTyping(t *testing.T) *[]string {
+	t.Helper()
+	var inserted []string
+	origType, origPaste := typeSegment, pasteSegment
+	t.Cleanup(func() { typeSegment, pasteSegment = origType, origPaste })
+
+	typeSegment = func(_ context.Context, text string, _ int) error {
+		inserted = append(inserted, "type:"+text)
+		return nil
+	}
+	pasteSegment = func(_ context.Context, text string) error {
+		inserted = append(inserted, "paste:"+text)
+		return nil
+	}
+	return &inserted
+}
+
+func TestIsUSLayout(t *testing.T) {
+	for layout, want := range map[string]bool{
+		"": true, "us": true, "US": true, "en-US": true, "en_us": true,
+		"de": false, "fr": false, "en-GB": false,
+	} {
+		if got := isUSLayout(layout); got != want {
+			t.Errorf("isUSLayout(%q) = %v, want %v", layout, got, want)
+		}
+	}
+}
+
+func TestSplitByLayout(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		layout string
+		want   []textSegment
+	}{
+		{"us types everything", "Grüße, Welt!", "us", []textSegment{{"Grüße, Welt!", false}}},
+		{"ascii on de", "Hello World 42", "de", []textSegment{{"Hello World 42", false}}},
+		{"punctuation on de", "Hello, World!", "de", []textSegment{
+			{"Hello", false}, {",", true}, {" World", false}, {"!", true},
+		}},
+		{"umlauts on de", "Grüße", "de", []textSegment{{"Gr", false}, {"üß", true}, {"e", false}}},
+		{"qwertz letters on de", "lazy 2024", "de", []textSegment{{"la", false}, {"zy", true}, {" 2024", false}}},
+		{"azerty letters on fr", "aqua wiz", "fr", []textSegment{{"aq", true}, {"u", false}, {"a", true}, {" ", false}, {"w", true}, {"i", false}, {"z", true}}},
+		{"digits on fr", "line 12", "fr", []textSegment{{"line ", false}, {"12", true}}},
+		{"leading paste", "@home", "fr", []textSegment{{"@", true}, {"ho", false}, {"m", true}, {"e", false}}},
+		{"case and separator insensitive", "Yes", "EN_GB", []textSegment{{"Yes", false}}},
+		{"unknown layout", "ab 1", "dvorak", []textSegment{{"ab", true}, {" ", false}, {"1", true}}},
+		{"empty", "", "de", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitByLayout(tt.text, tt.layout); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitByLayout(%q, %q) = %v, want %v", tt.text, tt.layout, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeToolLayoutRouting(t *testing.T) {
+	tests := []struct {
+		layout string
+		want   []string
+	}{
+		{"", []string{"type:user@example.com"}},
+		{"de", []string{"type:user", "paste:@", "type:example", "paste:.", "type:com"}},
+		{"fr", []string{"type:user", "paste:@", "type:ex", "paste:am", "type:ple", "paste:.", "type:co", "paste:m"}},
+	}
+	for _, tt := range tests {
+		t.Run("layout "+tt.layout, func(t *testing.T) {
+			inserted := fakeTyping(t)
+			tool := NewTypeTool()
+			tool.KeyboardLayout = tt.layout
+
+			out, err := tool.Execute(context.Background(), `{"text":"user@example.com"}`)
+			if err != nil {
+				t.Fatalf("Execute: %v", err)
+			}
+			if !reflect.DeepEqual(*inserted, tt.want) {
+				t.Errorf("inserted %v, want %v", *inserted, tt.want)
+			}
+
+			var result map[string]any
+			_ = json.Unmarshal([]byte(out), &result)
+			if got, want := result["keyboard_layout"] != nil, !isUSLayout(tt.layout); got != want {
+				t.Errorf("keyboard_layout reported = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTypeToolPastesLongText(t *testing.T) {
+	text := strings.Repeat("a", 11)
+	tests := []struct {
+		name      string
+		threshold int
+		want      []string
+		method    string
+	}{
+		{"never by default", 0, []string{"type:" + text}, typeMethod},
+		{"above threshold", 10, []string{"paste:" + text}, "paste"},
+		{"at threshold", 11, []string{"type:" + text}, typeMethod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inserted := fakeTyping(t)
+			tool := NewTypeTool()
+			tool.PasteThreshold = tt.threshold
+
+			out, _ := tool.Execute(context.Background(), `{"text":"`+text+`"}`)
+			if !reflect.DeepEqual(*inserted, tt.want) {
+				t.Errorf("inserted %v, want %v", *inserted, tt.want)
+			}
+			if !strings.Contains(out, `"method":"`+tt.method+`"`) {
+				t.Errorf("result = %s, want method %s", out, tt.method)
+			}
+		})
+	}
+}
@@ -10,13 +10,13 @@ import (
 // MoveTool moves the mouse cursor to a position using normalized coordinates (0-1000 scale).
 type MoveTool struct {
 	BaseTool
-	// ScreenIndex specifies which screen to use (default: 0 = primary).
-	ScreenIndex int
+	// State holds settings shared with the agent's other tools, such as the screen.
+	State *State
 }
 
 // NewMoveTool creates a new move tool.
 func NewMoveTool() *MoveTool {
-	return &MoveTool{ScreenIndex: 0}
+	return &MoveTool{State: NewState()}
 }
 
 func (t *MoveTool) Name() string {
@@ -68,16 +68,12 @@ func (t *MoveTool) Execute(ctx context.Context, argsJSON string) (string, error)
 	}
 
 	// Get screen info
-	screenIndex := args.ScreenIndex
-	if screenIndex == 0 && t.ScreenIndex != 0 {
-		screenIndex = t.ScreenIndex
-	}
+	screenIndex := t.State.resolveScreen(args.ScreenIndex)
 	screen := coords.GetScreen(screenIndex)
 
 	// Convert normalized coordinates (0-1000) to absolute screen coordinates
 	// Standard mapping: 0=left/top, 1000=right/bottom (matches TuriX-CUA)
-	screenX := screen.X + int(float64(args.X)/1000.0*float64(screen.Width))
-	screenY := screen.Y + int(float64(args.Y)/1000.0*float64(screen.Height))
+	screenX, screenY := t.State.toScreen(args.X, args.Y, screen)
 
 	// Move cursor
 	robotgo.Move(screenX, screenY)
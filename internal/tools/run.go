@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"image"
+	"sync"
+	"time"
+)
+
+// runKey is the context key for per-run tool state.
+type runKey struct{}
+
+// runState is tool state scoped to a single agent run. Unlike State, which the
+// tools of one agent share, each run gets its own, so concurrent runs don't
+// see each other's frames, throttled captures, or pointer actions.
+type runState struct {
+	mu sync.Mutex
+
+	frameHash [sha256.Size]byte // Hash of the most recent screenshot sent to the model
+	hasFrame  bool
+
+	lastAction    image.Point // Global screen position of the last pointer action
+	hasLastAction bool
+
+	captureAt     time.Time              // When captureResult was captured
+	captureArgs   string                 // Arguments that produced captureResult
+	captureResult map[string]interface{} // Most recent full screenshot result
+}
+
+// WithRunState attaches fresh per-run tool state to ctx. Screenshot
+// deduplication and throttling only apply within a context that has it, i.e.
+// within a single run.
+func WithRunState(ctx context.Context) context.Context {
+	return context.WithValue(ctx, runKey{}, &runState{})
+}
+
+// runStateFrom returns the run's tool state, or nil if there is none.
+func runStateFrom(ctx context.Context) *runState {
+	state, _ := ctx.Value(runKey{}).(*runState)
+	return state
+}
+
+// InvalidateCaptures drops the run's throttled screenshot, so the next capture
+// after an action shows its effect. It is a no-op outside a run.
+func InvalidateCaptures(ctx context.Context) {
+	if r := runStateFrom(ctx); r != nil {
+		r.mu.Lock()
+		r.captureResult = nil
+		r.mu.Unlock()
+	}
+}
+
+// unchanged records the hash of the frame about to be sent and reports whether
+// it equals the previous one.
+func (r *runState) unchanged(hash [sha256.Size]byte) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	same := r.hasFrame && r.frameHash == hash
+	r.frameHash, r.hasFrame = hash, true
+	return same
+}
+
+// throttled returns a copy of the previous screenshot result, flagged as
+// throttled, if a capture with the same arguments happened less than interval
+// before now.
+func (r *runState) throttled(argsJSON string, interval time.Duration, now time.Time) (map[string]interface{}, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.captureResult == nil || argsJSON != r.captureArgs || now.Sub(r.captureAt) >= interval {
+		return nil, false
+	}
+
+	cached := make(map[string]interface{}, len(r.captureResult)+1)
+	for k, v := range r.captureResult {
+		cached[k] = v
+	}
+	cached["throttled"] = true
+	return cached, true
+}
+
+// storeCapture remembers a screenshot result for throttling.
+func (r *runState) storeCapture(argsJSON string, result map[string]interface{}, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.captureAt, r.captureArgs, r.captureResult = at, argsJSON, result
+}
+
+// recordLastAction records the global screen position of a pointer action in
+// the run's state, or in the shared state outside a run.
+func recordLastAction(ctx context.Context, shared *State, x, y int) {
+	r := runStateFrom(ctx)
+	if r == nil {
+		shared.SetLastAction(x, y)
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lastAction, r.hasLastAction = image.Pt(x, y), true
+}
+
+// lastActionFrom returns the position of the run's most recent pointer action,
+// or the shared state's outside a run, and whether there has been one.
+func lastActionFrom(ctx context.Context, shared *State) (image.Point, bool) {
+	r := runStateFrom(ctx)
+	if r == nil {
+		return shared.LastAction()
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.lastAction, r.hasLastAction
+}
@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"image"
+	"testing"
+	"time"
+)
+
+func TestRunStateThrottled(t *testing.T) {
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	interval := time.Second
+
+	tests := []struct {
+		name  string
+		args  string
+		after time.Duration
+		want  bool
+	}{
+		{"same args within interval", `{}`, 500 * time.Millisecond, true},
+		{"same args just before interval", `{}`, interval - time.Nanosecond, true},
+		{"same args at interval", `{}`, interval, false},
+		{"same args after interval", `{}`, 2 * interval, false},
+		{"different args within interval", `{"focus":true}`, 100 * time.Millisecond, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &runState{}
+			r.storeCapture(`{}`, map[string]interface{}{"image_base64": "abc"}, start)
+
+			got, ok := r.throttled(tt.args, interval, start.Add(tt.after))
+			if ok != tt.want {
+				t.Fatalf("throttled = %v, want %v", ok, tt.want)
+			}
+			if ok && (got["throttled"] != true || got["image_base64"] != "abc") {
+				t.Errorf("cached result = %v, want the stored frame flagged as throttled", got)
+			}
+		})
+	}
+}
+
+func TestRunStateThrottledDoesNotMutateStored(t *testing.T) {
+	start := time.Now()
+	r := &runState{}
+	stored := map[string]interface{}{"image_base64": "abc"}
+	r.storeCapture(`{}`, stored, start)
+
+	if _, ok := r.throttled(`{}`, time.Second, start); !ok {
+		t.Fatal("expected a throttled result")
+	}
+	if _, ok := stored["throttled"]; ok {
+		t.Error("throttled flag leaked into the stored result")
+	}
+}
+
+func TestInvalidateCaptures(t *testing.T) {
+	ctx := WithRunState(context.Background())
+	r := runStateFrom(ctx)
+	now := time.Now()
+	r.storeCapture(`{}`, map[string]interface{}{}, now)
+
+	InvalidateCaptures(ctx)
+	if _, ok := r.throttled(`{}`, time.Hour, now); ok {
+		t.Error("capture still served after invalidation")
+	}
+
+	// Outside a run it is a no-op
+	InvalidateCaptures(context.Background())
+}
+
+func TestRunStateUnchanged(t *testing.T) {
+	r := &runState{}
+	a := sha256.Sum256([]byte("a"))
+	b := sha256.Sum256([]byte("b"))
+
+	steps := []struct {
+		hash [sha256.Size]byte
+		want bool
+	}{
+		{a, false}, // First frame is never a repeat
+		{a, true},
+		{b, false},
+		{b, true},
+		{a, false},
+	}
+	for i, s := range steps {
+		if got := r.unchanged(s.hash); got != s.want {
+			t.Errorf("step %d: unchanged = %v, want %v", i, got, s.want)
+		}
+	}
+}
+
+func TestLastActionPerRun(t *testing.T) {
+	shared := NewState()
+	run1 := WithRunState(context.Background())
+	run2 := WithRunState(context.Background())
+
+	recordLastAction(run1, shared, 10, 20)
+
+	if got, ok := lastActionFrom(run1, shared); !ok || got != image.Pt(10, 20) {
+		t.Errorf("run 1 last action = %v, %v; want (10,20), true", got, ok)
+	}
+	if _, ok := lastActionFrom(run2, shared); ok {
+		t.Error("run 2 sees run 1's action")
+	}
+	if _, ok := shared.LastAction(); ok {
+		t.Error("a run's action leaked into the shared state")
+	}
+
+	// Outside a run, actions go to the shared state
+	recordLastAction(context.Background(), shared, 30, 40)
+	if got, ok := lastActionFrom(context.Background(), shared); !ok || got != image.Pt(30, 40) {
+		t.Errorf("shared last action = %v, %v; want (30,40), true", got, ok)
+	}
+	if got, _ := lastActionFrom(run1, shared); got != image.Pt(10, 20) {
+		t.Errorf("run 1 last action changed to %v", got)
+	}
+}
@@ -1,14 +1,14 @@
 package tools
 
 import (
-	"bytes"
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"image"
-	"image/jpeg"
+	"sync"
+	"time"
 
 	"github.com/anxuanzi/cua/internal/coords"
+	"github.com/anxuanzi/cua/pkg/screen"
 	"github.com/go-vgo/robotgo"
 	"golang.org/x/image/draw"
 )
@@ -23,18 +23,47 @@ const (
 	// Lower values = smaller files = fewer tokens, but less detail.
 	// 60-70 provides good balance for UI recognition.
 	DefaultJPEGQuality = 65
+	// DefaultFocusRadius is the half-size of a focus capture in normalized units.
+	DefaultFocusRadius = 150
+)
+
+// screenFor and captureImage are the display backend of the screenshot tool.
+// They are variables so the tool can be driven by a fake backend.
+var (
+	screenFor    = coords.GetScreen
+	captureImage = func() (image.Image, error) { return robotgo.CaptureImg() }
 )
 
 // ScreenshotTool captures screenshots of the screen.
 type ScreenshotTool struct {
 	BaseTool
-	// ScreenIndex specifies which screen to capture (default: 0 = primary).
-	ScreenIndex int
+	// State holds settings shared with the agent's other tools, such as the screen.
+	State *State
+	// Hook, if set, post-processes each capture before it is encoded.
+	Hook screen.CaptureHook
+	// Scaler selects the downscaling interpolation (default: bilinear).
+	Scaler screen.Scaler
+	// ShowCursor draws the mouse pointer onto captures, which otherwise omit it.
+	ShowCursor bool
+	// Grayscale converts captures to grayscale before encoding.
+	Grayscale bool
+	// Palette, if > 0, quantizes captures to at most Palette colors before encoding.
+	Palette int
+	// Dedup replaces a capture identical to the previous one in the same run
+	// with a short "unchanged" result instead of the full image.
+	Dedup bool
+	// Throttle, if > 0, is the minimum interval between captures in a run. A
+	// repeated call within it returns the previous result, flagged as
+	// throttled, unless a tool has acted since (see InvalidateCaptures).
+	Throttle time.Duration
+
+	mu        sync.Mutex
+	lastFrame []byte // Most recent JPEG sent to the model
 }
 
 // NewScreenshotTool creates a new screenshot tool.
 func NewScreenshotTool() *ScreenshotTool {
-	return &ScreenshotTool{ScreenIndex: 0}
+	return &ScreenshotTool{State: NewState()}
 }
 
 func (t *ScreenshotTool) Name() string {
@@ -53,25 +82,53 @@ func (t *ScreenshotTool) Parameters() map[string]ParameterSpec {
 			Required:    false,
 			Default:     0,
 		},
+		"focus": {
+			Type:        "boolean",
+			Description: "Capture only a region around the focus target instead of the full screen. Cheaper and sharper for local actions such as verifying a click. The result includes focus_region for translating coordinates back.",
+			Required:    false,
+			Default:     false,
+		},
+		"focus_target": {
+			Type:        "string",
+			Description: "What to center the focus region on: 'cursor' (mouse pointer) or 'last_action' (last click, drag, or scroll). Default: cursor",
+			Required:    false,
+			Default:     "cursor",
+			Enum:        []interface{}{"cursor", "last_action"},
+		},
+		"focus_radius": {
+			Type:        "integer",
+			Description: "Half-size of the focus region in 0-1000 normalized units (default: 150)",
+			Required:    false,
+			Default:     DefaultFocusRadius,
+		},
 	}
 }
 
 func (t *ScreenshotTool) Execute(ctx context.Context, argsJSON string) (string, error) {
 	var args struct {
-		ScreenIndex int `json:"screen_index"`
+		ScreenIndex int    `json:"screen_index"`
+		Focus       bool   `json:"focus"`
+		FocusTarget string `json:"focus_target"`
+		FocusRadius int    `json:"focus_radius"`
 	}
 	if err := ParseArgs(argsJSON, &args); err != nil {
 		return ErrorResponse("invalid arguments: "+err.Error(), "Provide valid JSON with optional screen_index"), nil
 	}
 
-	// Use configured screen index if not specified
-	screenIndex := args.ScreenIndex
-	if screenIndex == 0 && t.ScreenIndex != 0 {
-		screenIndex = t.ScreenIndex
+	// Reuse a very recent identical capture rather than hammering the display
+	run := runStateFrom(ctx)
+	if t.Throttle > 0 && run != nil {
+		if cached, ok := run.throttled(argsJSON, t.Throttle, time.Now()); ok {
+			resultJSON, _ := json.Marshal(cached)
+			return string(resultJSON), nil
+		}
 	}
 
+	// Use configured screen index if not specified
+	screenIndex := t.State.resolveScreen(args.ScreenIndex)
+
 	// Get screen info first - we need logical dimensions for coordinate system
-	screen := coords.GetScreen(screenIndex)
+	screenInfo := screenFor(screenIndex)
 
 	// Set display for capture
 	oldDisplayID := robotgo.DisplayID
@@ -79,13 +136,11 @@ func (t *ScreenshotTool) Execute(ctx context.Context, argsJSON string) (string,
 	defer func() { robotgo.DisplayID = oldDisplayID }()
 
 	// Capture screenshot
-	img, err := robotgo.CaptureImg()
+	capturedAt := time.Now()
+	img, err := screen.CaptureWithRetry(captureImage)
 	if err != nil {
 		return ErrorResponse("failed to capture screenshot: "+err.Error(), "Ensure screen permissions are granted"), nil
 	}
-	if img == nil {
-		return ErrorResponse("failed to capture screenshot: nil image", "Ensure screen permissions are granted"), nil
-	}
 
 	// Get physical capture dimensions
 	bounds := img.Bounds()
@@ -93,47 +148,202 @@ func (t *ScreenshotTool) Execute(ctx context.Context, argsJSON string) (string,
 
 	// Calculate actual scale factor from capture vs logical dimensions
 	// On Retina displays, capture is typically 2x the logical resolution
-	actualScaleFactor := float64(captureW) / float64(screen.Width)
+	actualScaleFactor := float64(captureW) / float64(screenInfo.Width)
 	if actualScaleFactor < 1.0 {
 		actualScaleFactor = 1.0
 	}
 
+	// The logical region of the screen to send, relative to the screen's origin.
+	// Normalized coordinates refer to the capture area (the whole screen unless
+	// captures are cropped to a region); a focus capture zooms into part of it.
+	area := t.State.captureArea(screenInfo)
+	cropped := area != image.Rect(0, 0, screenInfo.Width, screenInfo.Height)
+	region := area
+	focused := false
+	if args.Focus {
+		if center, ok := t.focusCenter(ctx, args.FocusTarget); ok {
+			if r, ok := focusRegion(screenInfo, center, args.FocusRadius); ok {
+				if r = r.Intersect(area); !r.Empty() {
+					region, focused = r, true
+				}
+			}
+		}
+	}
+
+	// Map the logical region to capture pixels
+	srcRect := image.Rect(
+		bounds.Min.X+int(float64(region.Min.X)*actualScaleFactor),
+		bounds.Min.Y+int(float64(region.Min.Y)*actualScaleFactor),
+		bounds.Min.X+int(float64(region.Max.X)*actualScaleFactor),
+		bounds.Min.Y+int(float64(region.Max.Y)*actualScaleFactor),
+	).Intersect(bounds)
+
 	// Calculate scaled dimensions for LLM using LOGICAL dimensions as reference
 	// This ensures the aspect ratio matches the coordinate system the LLM should use
-	newW, newH := calculateScaledDimensions(screen.Width, screen.Height, MaxScreenshotWidth, MaxScreenshotHeight)
+	newW, newH := calculateScaledDimensions(region.Dx(), region.Dy(), MaxScreenshotWidth, MaxScreenshotHeight)
 
-	// Resize using high-quality CatmullRom scaling
+	// Resize with the configured scaler (bilinear by default for speed)
 	resized := image.NewRGBA(image.Rect(0, 0, newW, newH))
-	draw.CatmullRom.Scale(resized, resized.Bounds(), img, bounds, draw.Over, nil)
+	t.Scaler.Interpolator().Scale(resized, resized.Bounds(), img, srcRect, draw.Over, nil)
+
+	if t.ShowCursor {
+		// Convert the global logical cursor position to image space
+		mx, my := robotgo.Location()
+		cx := (mx - screenInfo.X - region.Min.X) * newW / region.Dx()
+		cy := (my - screenInfo.Y - region.Min.Y) * newH / region.Dy()
+		screen.DrawCursor(resized, cx, cy)
+	}
+
+	// Let integrators annotate, redact, or audit the frame before encoding
+	if t.Hook != nil {
+		meta := screen.CaptureMeta{
+			ScreenIndex:    screenIndex,
+			OriginalWidth:  srcRect.Dx(),
+			OriginalHeight: srcRect.Dy(),
+			ScaledWidth:    newW,
+			ScaledHeight:   newH,
+			CapturedAt:     capturedAt,
+		}
+		if hooked := t.Hook(resized, meta); hooked != nil {
+			resized = hooked
+		}
+	}
+
+	// Drop color detail the model may not need, so frames encode smaller
+	if t.Grayscale {
+		screen.Grayscale(resized)
+	}
+	if t.Palette > 0 {
+		screen.Quantize(resized, t.Palette)
+	}
+
+	// Skip resending a frame the model has just seen (e.g., an action that changed nothing)
+	if t.Dedup && run != nil && run.unchanged(hashFrame(resized, screenIndex, region)) {
+		resultJSON, _ := json.Marshal(map[string]interface{}{
+			"unchanged":    true,
+			"note":         "No visual change since your last screenshot; the previous image is still current. If you just acted, the action had no visible effect.",
+			"screen_index": screenIndex,
+		})
+		return string(resultJSON), nil
+	}
 
-	// Encode to JPEG with compression for token efficiency
-	var buf bytes.Buffer
-	if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: DefaultJPEGQuality}); err != nil {
+	// Encode to JPEG with compression for token efficiency. Tool results are
+	// JSON text, so the frame travels as a base64 block whatever the provider.
+	part, err := screen.EncodeForModel(resized, screen.ProviderAnthropic, t.State.JPEGQuality())
+	if err != nil {
 		return ErrorResponse("failed to encode screenshot: "+err.Error(), ""), nil
 	}
 
-	// Base64 encode
-	b64 := base64.StdEncoding.EncodeToString(buf.Bytes())
+	t.mu.Lock()
+	t.lastFrame = part.Data
+	t.mu.Unlock()
 
 	// Simplified response to avoid confusing the model with dimension details
 	// The model should treat this as a full-screen image and estimate positions as percentages
 	result := map[string]interface{}{
-		"image_base64": b64,
+		"image_base64": part.Base64,
 		// Simple message to remind model about coordinate system
 		"note": "This image shows the FULL SCREEN. Use 0-1000 normalized coordinates based on visual percentage position.",
 		// Minimal metadata for debugging only
 		"screen_index": screenIndex,
 	}
+	if cropped {
+		result["note"] = "This image shows your WORKING AREA, a fixed region of the screen. " +
+			"Use 0-1000 normalized coordinates based on visual percentage position within this image; actions outside it are not possible."
+	}
+	if focused {
+		// Report the crop in normalized capture-area coordinates so actions can be translated back
+		result["note"] = "This image shows only a REGION of the screen, not the full screen. " +
+			"To act on a point, convert it: screen_x = focus_region.x + image_fraction_x * focus_region.width (same for y), in 0-1000 normalized coordinates."
+		result["focus_region"] = normalizedWithin(region, area)
+	} else if args.Focus {
+		result["focus_fallback"] = "focus target is unavailable or not in the capture area; captured the whole area"
+	}
+	if err := screen.CheckCaptureScale(screenInfo.Width, screenInfo.Height, bounds); err != nil {
+		// Surface it so scale-factor problems behind misplaced clicks can be diagnosed
+		result["scale_warning"] = err.Error()
+	}
+
+	if t.Throttle > 0 && run != nil {
+		run.storeCapture(argsJSON, result, capturedAt)
+	}
 
 	resultJSON, _ := json.Marshal(result)
 	return string(resultJSON), nil
 }
 
+// LastFrame returns the most recent JPEG screenshot sent to the model, or nil.
+func (t *ScreenshotTool) LastFrame() []byte {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.lastFrame
+}
+
 // Run implements the interfaces.Tool Run method by delegating to Execute.
 func (t *ScreenshotTool) Run(ctx context.Context, input string) (string, error) {
 	return t.Execute(ctx, input)
 }
 
+// focusCenter returns the global screen position to center a focus capture on.
+// It reports false for "last_action" when no pointer action has happened yet.
+func (t *ScreenshotTool) focusCenter(ctx context.Context, target string) (image.Point, bool) {
+	if target == "last_action" {
+		return lastActionFrom(ctx, t.State)
+	}
+	mx, my := robotgo.Location()
+	return image.Pt(mx, my), true
+}
+
+// focusRegion returns the logical region, relative to the screen's origin,
+// within radius normalized units of center (a global screen position). It
+// reports false when center is not on the screen.
+func focusRegion(screenInfo coords.ScreenInfo, center image.Point, radius int) (image.Rectangle, bool) {
+	if radius <= 0 {
+		radius = DefaultFocusRadius
+	}
+
+	point := image.Pt(center.X-screenInfo.X, center.Y-screenInfo.Y)
+	full := image.Rect(0, 0, screenInfo.Width, screenInfo.Height)
+	if !point.In(full) {
+		return image.Rectangle{}, false
+	}
+
+	rx := radius * screenInfo.Width / coords.NormalizedMax
+	ry := radius * screenInfo.Height / coords.NormalizedMax
+	region := image.Rect(point.X-rx, point.Y-ry, point.X+rx, point.Y+ry).Intersect(full)
+	if region.Empty() {
+		return image.Rectangle{}, false
+	}
+	return region, true
+}
+
+// normalizedRegion converts a region in 0-1000 normalized coordinates to a
+// logical region relative to the screen's origin, clipped to the screen.
+// It reports false if the region is empty.
+func normalizedRegion(screenInfo coords.ScreenInfo, norm image.Rectangle) (image.Rectangle, bool) {
+	region := image.Rect(
+		norm.Min.X*screenInfo.Width/coords.NormalizedMax,
+		norm.Min.Y*screenInfo.Height/coords.NormalizedMax,
+		norm.Max.X*screenInfo.Width/coords.NormalizedMax,
+		norm.Max.Y*screenInfo.Height/coords.NormalizedMax,
+	).Intersect(image.Rect(0, 0, screenInfo.Width, screenInfo.Height))
+	if region.Empty() {
+		return image.Rectangle{}, false
+	}
+	return region, true
+}
+
+// normalizedWithin expresses region, a logical region relative to the
+// screen's origin, in 0-1000 normalized coordinates of area.
+func normalizedWithin(region, area image.Rectangle) map[string]int {
+	return map[string]int{
+		"x":      (region.Min.X - area.Min.X) * coords.NormalizedMax / area.Dx(),
+		"y":      (region.Min.Y - area.Min.Y) * coords.NormalizedMax / area.Dy(),
+		"width":  region.Dx() * coords.NormalizedMax / area.Dx(),
+		"height": region.Dy() * coords.NormalizedMax / area.Dy(),
+	}
+}
+
 // calculateScaledDimensions calculates new dimensions that fit within max bounds
 // while preserving aspect ratio.
 func calculateScaledDimensions(origW, origH, maxW, maxH int) (newW, newH int) {
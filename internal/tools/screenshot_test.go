@@ -0,0 +1,313 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/anxuanzi/cua/internal/coords"
+	"github.com/anxuanzi/cua/pkg/screen"
+	"golang.org/x/image/draw"
+)
+
+func TestFocusRegion(t *testing.T) {
+	primary := coords.ScreenInfo{Width: 2000, Height: 1000}
+	secondary := coords.ScreenInfo{Index: 1, X: 2000, Width: 2000, Height: 1000}
+
+	tests := []struct {
+		name   string
+		screen coords.ScreenInfo
+		center image.Point
+		radius int
+		want   image.Rectangle
+		wantOK bool
+	}{
+		{"centered", primary, image.Pt(1000, 500), 100, image.Rect(800, 400, 1200, 600), true},
+		{"default radius", primary, image.Pt(1000, 500), 0, image.Rect(700, 350, 1300, 650), true},
+		{"clipped at the corner", primary, image.Pt(50, 50), 100, image.Rect(0, 0, 250, 150), true},
+		{"relative to a secondary screen", secondary, image.Pt(2500, 500), 100, image.Rect(300, 400, 700, 600), true},
+		{"center on another screen", secondary, image.Pt(500, 500), 100, image.Rectangle{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := focusRegion(tt.screen, tt.center, tt.radius)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("focusRegion = (%v, %v), want (%v, %v)", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestNormalizedWithin(t *testing.T) {
+	tests := []struct {
+		name         string
+		region, area image.Rectangle
+		want         map[string]int
+	}{
+		{
+			"whole area",
+			image.Rect(0, 0, 2000, 1000), image.Rect(0, 0, 2000, 1000),
+			map[string]int{"x": 0, "y": 0, "width": 1000, "height": 1000},
+		},
+		{
+			"quarter of full screen",
+			image.Rect(500, 250, 1000, 500), image.Rect(0, 0, 2000, 1000),
+			map[string]int{"x": 250, "y": 250, "width": 250, "height": 250},
+		},
+		{
+			"relative to a cropped area",
+			image.Rect(600, 300, 800, 400), image.Rect(500, 200, 1500, 600),
+			map[string]int{"x": 100, "y": 250, "width": 200, "height": 250},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizedWithin(tt.region, tt.area); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("normalizedWithin = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCalculateScaledDimensions(t *testing.T) {
+	tests := []struct {
+		w, h, wantW, wantH int
+	}{
+		{1280, 720, 1280, 720},
+		{800, 600, 800, 600},
+		{2560, 1440, 1280, 720},
+		{2560, 1600, 1152, 720},
+		{3440, 1440, 1280, 535},
+	}
+	for _, tt := range tests {
+		w, h := calculateScaledDimensions(tt.w, tt.h, MaxScreenshotWidth, MaxScreenshotHeight)
+		if w != tt.wantW || h != tt.wantH {
+			t.Errorf("calculateScaledDimensions(%d, %d) = (%d, %d), want (%d, %d)", tt.w, tt.h, w, h, tt.wantW, tt.wantH)
+		}
+	}
+}
+
+// fakeDisplay replaces the screenshot backend with a display of info whose
+// captures return frames in turn, repeating the last one.
+func fakeDisplay(t *testing.T, info coords.ScreenInfo, frames ...image.Image) {
+	t.Helper()
+	origScreen, origCapture := screenFor, captureImage
+	t.Cleanup(func() { screenFor, captureImage = origScreen, origCapture })
+
+	calls := 0
+	screenFor = func(int) coords.ScreenInfo { return info }
+	captureImage = func() (image.Image, error) {
+		frame := frames[min(calls, len(frames)-1)]
+		calls++
+		return frame, nil
+	}
+}
+
+// executeScreenshot runs tool with argsJSON and decodes its result.
+func executeScreenshot(t *testing.T, ctx context.Context, tool *ScreenshotTool, argsJSON string) map[string]any {
+	t.Helper()
+	out, err := tool.Execute(ctx, argsJSON)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	var result map[string]any
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("result %s: %v", out, err)
+	}
+	if result["error"] != nil {
+		t.Fatalf("screenshot failed: %v", result["error"])
+	}
+	return result
+}
+
+func TestScreenshotScaleWarning(t *testing.T) {
+	tests := []struct {
+		name        string
+		capture     image.Rectangle
+		wantWarning bool
+	}{
+		{"matching capture", image.Rect(0, 0, 1440, 900), false},
+		{"retina capture", image.Rect(0, 0, 2880, 1800), false},
+		{"mismatched capture", image.Rect(0, 0, 1920, 1080), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDisplay(t, coords.ScreenInfo{Width: 1440, Height: 900, ScaleFactor: 1}, image.NewRGBA(tt.capture))
+
+			result := executeScreenshot(t, context.Background(), NewScreenshotTool(), "{}")
+			warning, _ := result["scale_warning"].(string)
+			if (warning != "") != tt.wantWarning {
+				t.Errorf("scale_warning = %q, want warning %v", warning, tt.wantWarning)
+			}
+			if result["image_base64"] == nil {
+				t.Error("no image in the result")
+			}
+		})
+	}
+}
+
+func TestHashFrame(t *testing.T) {
+	frame := func(v byte) *image.RGBA {
+		img := image.NewRGBA(image.Rect(0, 0, 8, 8))
+		for i := range img.Pix {
+			img.Pix[i] = v
+		}
+		return img
+	}
+	region := image.Rect(0, 0, 100, 100)
+	base := hashFrame(frame(1), 0, region)
+
+	if hashFrame(frame(1), 0, region) != base {
+		t.Error("identical frames hash differently")
+	}
+	for name, other := range map[string][32]byte{
+		"pixels": hashFrame(frame(2), 0, region),
+		"screen": hashFrame(frame(1), 1, region),
+		"region": hashFrame(frame(1), 0, image.Rect(0, 0, 100, 50)),
+	} {
+		if other == base {
+			t.Errorf("frames differing in %s hash the same", name)
+		}
+	}
+}
+
+func TestScreenshotDedup(t *testing.T) {
+	still := image.NewRGBA(image.Rect(0, 0, 640, 400))
+	changed := image.NewRGBA(image.Rect(0, 0, 640, 400))
+	changed.Pix[0] = 255
+
+	fakeDisplay(t, coords.ScreenInfo{Width: 640, Height: 400, ScaleFactor: 1}, still, still, changed, changed)
+	tool := NewScreenshotTool()
+	tool.Dedup = true
+	ctx := WithRunState(context.Background())
+
+	want := []bool{false, true, false, true} // Whether each capture is reported unchanged
+	for i, wantUnchanged := range want {
+		result := executeScreenshot(t, ctx, tool, "{}")
+		unchanged := result["unchanged"] == true
+		if unchanged != wantUnchanged {
+			t.Errorf("capture %d unchanged = %v, want %v", i, unchanged, wantUnchanged)
+		}
+		if hasImage := result["image_base64"] != nil; hasImage == unchanged {
+			t.Errorf("capture %d: image sent = %v with unchanged = %v", i, hasImage, unchanged)
+		}
+	}
+}
+
+func TestScreenshotDedupPerRun(t *testing.T) {
+	still := image.NewRGBA(image.Rect(0, 0, 640, 400))
+	fakeDisplay(t, coords.ScreenInfo{Width: 640, Height: 400, ScaleFactor: 1}, still)
+	tool := NewScreenshotTool()
+	tool.Dedup = true
+
+	// A new run, or no run at all, always gets the full image
+	for i, ctx := range []context.Context{
+		WithRunState(context.Background()),
+		WithRunState(context.Background()),
+		context.Background(),
+		context.Background(),
+	} {
+		if result := executeScreenshot(t, ctx, tool, "{}"); result["unchanged"] == true {
+			t.Errorf("capture %d reported unchanged outside its run", i)
+		}
+	}
+}
+
+func TestScreenshotHook(t *testing.T) {
+	red := color.RGBA{255, 0, 0, 255}
+	tests := []struct {
+		name    string
+		hook    func(img *image.RGBA) *image.RGBA
+		wantRed bool
+	}{
+		{"modify in place", func(img *image.RGBA) *image.RGBA {
+			draw.Draw(img, img.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+			return nil
+		}, true},
+		{"replace", func(img *image.RGBA) *image.RGBA {
+			out := image.NewRGBA(img.Bounds())
+			draw.Draw(out, out.Bounds(), image.NewUniform(red), image.Point{}, draw.Src)
+			return out
+		}, true},
+		{"keep", func(*image.RGBA) *image.RGBA { return nil }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDisplay(t, coords.ScreenInfo{Index: 1, Width: 2560, Height: 1440, ScaleFactor: 1}, image.NewRGBA(image.Rect(0, 0, 2560, 1440)))
+
+			var meta screen.CaptureMeta
+			tool := NewScreenshotTool()
+			tool.State.SetScreenIndex(1)
+			tool.Hook = func(img *image.RGBA, m screen.CaptureMeta) *image.RGBA {
+				meta = m
+				return tt.hook(img)
+			}
+			executeScreenshot(t, context.Background(), tool, "{}")
+
+			want := screen.CaptureMeta{ScreenIndex: 1, OriginalWidth: 2560, OriginalHeight: 1440, ScaledWidth: 1280, ScaledHeight: 720}
+			got := meta
+			got.CapturedAt = time.Time{}
+			if got != want || meta.CapturedAt.IsZero() {
+				t.Errorf("hook meta = %+v, want %+v with a capture time", meta, want)
+			}
+
+			frame, err := jpeg.Decode(bytes.NewReader(tool.LastFrame()))
+			if err != nil {
+				t.Fatalf("decode frame: %v", err)
+			}
+			r, _, _, _ := frame.At(640, 360).RGBA()
+			if isRed := r>>8 > 200; isRed != tt.wantRed {
+				t.Errorf("encoded frame red = %v, want %v", isRed, tt.wantRed)
+			}
+		})
+	}
+}
+
+func TestScreenshotFocusLastAction(t *testing.T) {
+	const args = `{"focus": true, "focus_target": "last_action", "focus_radius": 100}`
+	want := map[string]any{"x": 400.0, "y": 400.0, "width": 200.0, "height": 200.0}
+
+	tests := []struct {
+		name   string
+		ctx    func(shared *State) context.Context
+		wantOK bool
+	}{
+		{"action in the run", func(shared *State) context.Context {
+			ctx := WithRunState(context.Background())
+			recordLastAction(ctx, shared, 1000, 500)
+			return ctx
+		}, true},
+		{"action outside a run", func(shared *State) context.Context {
+			recordLastAction(context.Background(), shared, 1000, 500)
+			return context.Background()
+		}, true},
+		{"no action yet", func(*State) context.Context {
+			return WithRunState(context.Background())
+		}, false},
+		{"action in another run", func(shared *State) context.Context {
+			recordLastAction(WithRunState(context.Background()), shared, 1000, 500)
+			return WithRunState(context.Background())
+		}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fakeDisplay(t, coords.ScreenInfo{Width: 2000, Height: 1000, ScaleFactor: 1}, image.NewRGBA(image.Rect(0, 0, 2000, 1000)))
+			tool := NewScreenshotTool()
+
+			result := executeScreenshot(t, tt.ctx(tool.State), tool, args)
+			if tt.wantOK {
+				if got := result["focus_region"]; !reflect.DeepEqual(got, want) {
+					t.Errorf("focus_region = %v, want %v", got, want)
+				}
+			} else if result["focus_fallback"] == nil || result["focus_region"] != nil {
+				t.Errorf("result = %v, want a whole-screen fallback", result)
+			}
+		})
+	}
+}
@@ -11,13 +11,13 @@ import (
 // ScrollTool performs scroll operations using normalized coordinates (0-1000 scale).
 type ScrollTool struct {
 	BaseTool
-	// ScreenIndex specifies which screen to use (default: 0 = primary).
-	ScreenIndex int
+	// State holds settings shared with the agent's other tools, such as the screen.
+	State *State
 }
 
 // NewScrollTool creates a new scroll tool.
 func NewScrollTool() *ScrollTool {
-	return &ScrollTool{ScreenIndex: 0}
+	return &ScrollTool{State: NewState()}
 }
 
 func (t *ScrollTool) Name() string {
@@ -98,19 +98,16 @@ func (t *ScrollTool) Execute(ctx context.Context, argsJSON string) (string, erro
 	}
 
 	// Get screen info
-	screenIndex := args.ScreenIndex
-	if screenIndex == 0 && t.ScreenIndex != 0 {
-		screenIndex = t.ScreenIndex
-	}
+	screenIndex := t.State.resolveScreen(args.ScreenIndex)
 	screen := coords.GetScreen(screenIndex)
 
 	// Convert normalized coordinates (0-1000) to absolute screen coordinates
 	// Standard mapping: 0=left/top, 1000=right/bottom (matches TuriX-CUA)
-	screenX := screen.X + int(float64(args.X)/1000.0*float64(screen.Width))
-	screenY := screen.Y + int(float64(args.Y)/1000.0*float64(screen.Height))
+	screenX, screenY := t.State.toScreen(args.X, args.Y, screen)
 
 	// Move to position first
 	robotgo.Move(screenX, screenY)
+	recordLastAction(ctx, t.State, screenX, screenY)
 	time.Sleep(50 * time.Millisecond)
 
 	// Perform scroll
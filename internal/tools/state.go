@@ -0,0 +1,178 @@
+package tools
+
+import (
+	"image"
+	"sync"
+
+	"github.com/anxuanzi/cua/internal/coords"
+)
+
+// State is tool configuration shared by the tools of one agent. It is safe for
+// concurrent use, so settings can change at runtime (e.g., switching displays)
+// while tools are executing.
+type State struct {
+	mu          sync.RWMutex
+	screenIndex int
+	jpegQuality int
+	coordSnap   int
+
+	captureRegion    image.Rectangle // Normalized region screenshots are cropped to
+	hasCaptureRegion bool
+
+	lastAction    image.Point // Last pointer action outside a run (runs track their own)
+	hasLastAction bool
+}
+
+// NewState creates tool state with default settings.
+func NewState() *State {
+	return &State{jpegQuality: DefaultJPEGQuality}
+}
+
+// ScreenIndex returns the configured screen (0 = primary).
+func (s *State) ScreenIndex() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.screenIndex
+}
+
+// SetScreenIndex sets the screen that tools operate on.
+func (s *State) SetScreenIndex(index int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.screenIndex = index
+}
+
+// JPEGQuality returns the screenshot JPEG quality (0-100).
+func (s *State) JPEGQuality() int {
+	if s == nil {
+		return DefaultJPEGQuality
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.jpegQuality
+}
+
+// SetJPEGQuality sets the screenshot JPEG quality (0-100).
+func (s *State) SetJPEGQuality(quality int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jpegQuality = quality
+}
+
+// CoordSnap returns the grid, in pixels, that pointer coordinates snap to
+// (0 = no snapping).
+func (s *State) CoordSnap() int {
+	if s == nil {
+		return 0
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.coordSnap
+}
+
+// SetCoordSnap sets the grid, in pixels, that pointer coordinates snap to.
+// 0 disables snapping.
+func (s *State) SetCoordSnap(px int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.coordSnap = px
+}
+
+// CaptureRegion returns the region, in 0-1000 normalized screen coordinates,
+// that screenshots are cropped to, and whether one is set.
+func (s *State) CaptureRegion() (image.Rectangle, bool) {
+	if s == nil {
+		return image.Rectangle{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.captureRegion, s.hasCaptureRegion
+}
+
+// SetCaptureRegion crops screenshots to region, in 0-1000 normalized screen
+// coordinates. Pointer tools then take coordinates relative to the region, so
+// positions read off a cropped screenshot land on the right pixel. nil
+// restores full-screen captures and coordinates.
+func (s *State) SetCaptureRegion(region *image.Rectangle) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if region == nil {
+		s.captureRegion, s.hasCaptureRegion = image.Rectangle{}, false
+		return
+	}
+	s.captureRegion, s.hasCaptureRegion = *region, true
+}
+
+// captureArea returns the logical area of screen, relative to its origin,
+// that screenshots show and normalized coordinates refer to: the capture
+// region if one is set, otherwise the whole screen.
+func (s *State) captureArea(screen coords.ScreenInfo) image.Rectangle {
+	if norm, ok := s.CaptureRegion(); ok {
+		if area, ok := normalizedRegion(screen, norm); ok {
+			return area
+		}
+	}
+	return image.Rect(0, 0, screen.Width, screen.Height)
+}
+
+// toScreen converts a normalized 0-1000 point in the capture area to a
+// global screen position, applying the configured coordinate snapping.
+func (s *State) toScreen(normX, normY int, screen coords.ScreenInfo) (int, int) {
+	area := s.captureArea(screen)
+	x := screen.X + area.Min.X + int(float64(normX)/1000.0*float64(area.Dx()))
+	y := screen.Y + area.Min.Y + int(float64(normY)/1000.0*float64(area.Dy()))
+	return s.snap(x, y, screen)
+}
+
+// snap applies the configured coordinate snapping to a screen position.
+func (s *State) snap(x, y int, screen coords.ScreenInfo) (int, int) {
+	p := coords.Snap(coords.Point{X: x, Y: y}, screen, s.CoordSnap())
+	return p.X, p.Y
+}
+
+// LastAction returns the global screen position of the most recent click,
+// drag, or scroll made outside a run (e.g., via CUA.ExecuteTool), and whether
+// there has been one. Actions within a run are tracked per run instead.
+func (s *State) LastAction() (image.Point, bool) {
+	if s == nil {
+		return image.Point{}, false
+	}
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.lastAction, s.hasLastAction
+}
+
+// SetLastAction records the global screen position of a pointer action.
+func (s *State) SetLastAction(x, y int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastAction = image.Pt(x, y)
+	s.hasLastAction = true
+}
+
+// resolveScreen returns the screen a call should use: the call's screen_index
+// argument, or the configured screen when the argument is the default 0.
+func (s *State) resolveScreen(argIndex int) int {
+	if configured := s.ScreenIndex(); argIndex == 0 && configured != 0 {
+		return configured
+	}
+	return argIndex
+}
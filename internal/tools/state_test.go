@@ -0,0 +1,135 @@
+package tools
+
+import (
+	"image"
+	"testing"
+
+	"github.com/anxuanzi/cua/internal/coords"
+)
+
+func TestStateNilReceiver(t *testing.T) {
+	var s *State
+
+	// Setters are no-ops and getters return defaults on a nil State
+	s.SetScreenIndex(2)
+	s.SetJPEGQuality(90)
+	s.SetCoordSnap(8)
+	s.SetLastAction(10, 20)
+
+	if got := s.ScreenIndex(); got != 0 {
+		t.Errorf("ScreenIndex() = %d, want 0", got)
+	}
+	if got := s.JPEGQuality(); got != DefaultJPEGQuality {
+		t.Errorf("JPEGQuality() = %d, want %d", got, DefaultJPEGQuality)
+	}
+	if got := s.CoordSnap(); got != 0 {
+		t.Errorf("CoordSnap() = %d, want 0", got)
+	}
+	if _, ok := s.LastAction(); ok {
+		t.Error("LastAction() reported an action on a nil State")
+	}
+}
+
+func TestStateSetters(t *testing.T) {
+	s := NewState()
+	if got := s.JPEGQuality(); got != DefaultJPEGQuality {
+		t.Errorf("default JPEGQuality() = %d, want %d", got, DefaultJPEGQuality)
+	}
+
+	s.SetScreenIndex(1)
+	s.SetJPEGQuality(80)
+	s.SetCoordSnap(4)
+
+	if got := s.ScreenIndex(); got != 1 {
+		t.Errorf("ScreenIndex() = %d, want 1", got)
+	}
+	if got := s.JPEGQuality(); got != 80 {
+		t.Errorf("JPEGQuality() = %d, want 80", got)
+	}
+	if got := s.CoordSnap(); got != 4 {
+		t.Errorf("CoordSnap() = %d, want 4", got)
+	}
+}
+
+func TestStateResolveScreen(t *testing.T) {
+	tests := []struct {
+		configured, arg, want int
+	}{
+		{0, 0, 0},
+		{0, 2, 2},
+		{1, 0, 1},
+		{1, 2, 2},
+	}
+	for _, tt := range tests {
+		s := NewState()
+		s.SetScreenIndex(tt.configured)
+		if got := s.resolveScreen(tt.arg); got != tt.want {
+			t.Errorf("configured %d, arg %d: resolveScreen = %d, want %d", tt.configured, tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestStateToScreen(t *testing.T) {
+	screen := coords.ScreenInfo{X: 100, Y: 50, Width: 2000, Height: 1000}
+	region := image.Rect(250, 200, 750, 600) // Logical area (500,200)-(1500,600)
+
+	tests := []struct {
+		name         string
+		region       *image.Rectangle
+		snap         int
+		normX, normY int
+		wantX, wantY int
+	}{
+		{"full screen origin", nil, 0, 0, 0, 100, 50},
+		{"full screen center", nil, 0, 500, 500, 1100, 550},
+		{"region origin", &region, 0, 0, 0, 600, 250},
+		{"region center", &region, 0, 500, 500, 1100, 450},
+		{"region bottom-right", &region, 0, 1000, 1000, 1600, 650},
+		{"region with snap", &region, 10, 123, 457, 720, 430},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewState()
+			s.SetCaptureRegion(tt.region)
+			s.SetCoordSnap(tt.snap)
+
+			x, y := s.toScreen(tt.normX, tt.normY, screen)
+			if x != tt.wantX || y != tt.wantY {
+				t.Errorf("toScreen(%d, %d) = (%d, %d), want (%d, %d)", tt.normX, tt.normY, x, y, tt.wantX, tt.wantY)
+			}
+		})
+	}
+}
+
+func TestStateCaptureArea(t *testing.T) {
+	screen := coords.ScreenInfo{Width: 1000, Height: 800}
+	full := image.Rect(0, 0, 1000, 800)
+	inside := image.Rect(100, 100, 600, 500)
+	offScreen := image.Rect(1200, 0, 1500, 500)
+
+	tests := []struct {
+		name   string
+		region *image.Rectangle
+		want   image.Rectangle
+	}{
+		{"no region", nil, full},
+		{"region", &inside, image.Rect(100, 80, 600, 400)},
+		{"empty after clipping", &offScreen, full},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewState()
+			s.SetCaptureRegion(tt.region)
+			if got := s.captureArea(screen); got != tt.want {
+				t.Errorf("captureArea = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	s := NewState()
+	s.SetCaptureRegion(&inside)
+	s.SetCaptureRegion(nil)
+	if _, ok := s.CaptureRegion(); ok {
+		t.Error("SetCaptureRegion(nil) did not clear the region")
+	}
+}
@@ -0,0 +1,107 @@
+package tools
+
+import (
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// TruncationMarker is appended to results cut short by TruncateResult.
+const TruncationMarker = "...[truncated]"
+
+// TruncateResult shortens a tool result to at most maxChars characters before
+// it is fed back to the model (no limit if maxChars <= 0).
+//
+// JSON objects stay valid: the longest top-level arrays are shortened first,
+// with "truncated": true and an "<field>_total" count of the original items
+// added. If that is not enough, the result is replaced by an object carrying a
+// prefix of the original output, sized so the object fits in maxChars (if
+// maxChars is too small for any object, the raw prefix is returned). Screenshot results are never truncated since
+// a partial image is useless. Non-JSON results are cut with TruncationMarker.
+func TruncateResult(result string, maxChars int) string {
+	if maxChars <= 0 || utf8.RuneCountInString(result) <= maxChars {
+		return result
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(result), &obj); err != nil {
+		return truncateString(result, maxChars)
+	}
+	if _, ok := obj["image_base64"]; ok {
+		return result
+	}
+
+	for {
+		key, items := longestArray(obj)
+		if key == "" {
+			break
+		}
+		if _, ok := obj[key+"_total"]; !ok {
+			obj[key+"_total"] = len(items)
+		}
+		obj[key] = items[:len(items)/2]
+		obj["truncated"] = true
+
+		data, _ := json.Marshal(obj)
+		if utf8.RuneCount(data) <= maxChars {
+			return string(data)
+		}
+	}
+
+	// Arrays alone could not shrink it enough; keep a prefix of the raw output.
+	// Escaping can make the prefix longer once marshalled, so shrink it until
+	// the whole object fits.
+	fallback := map[string]interface{}{"truncated": true}
+	if success, ok := obj["success"]; ok {
+		fallback["success"] = success
+	}
+	for limit := maxChars; limit >= 0; {
+		fallback["output"] = truncateString(result, limit)
+		data, _ := json.Marshal(fallback)
+		overflow := utf8.RuneCount(data) - maxChars
+		if overflow <= 0 {
+			return string(data)
+		}
+		if limit == 0 {
+			break
+		}
+		// Each dropped rune shortens the output by at least one character
+		limit -= overflow
+		if limit < 0 {
+			limit = 0
+		}
+	}
+
+	// maxChars is too small for even an empty wrapper object
+	return truncateString(result, maxChars)
+}
+
+// longestArray returns the top-level array field with the most items, or ""
+// if no field holds a non-empty array.
+func longestArray(obj map[string]interface{}) (string, []interface{}) {
+	bestKey := ""
+	var best []interface{}
+	for key, value := range obj {
+		items, ok := value.([]interface{})
+		if !ok || len(items) == 0 {
+			continue
+		}
+		if len(items) > len(best) || (len(items) == len(best) && key < bestKey) {
+			bestKey, best = key, items
+		}
+	}
+	return bestKey, best
+}
+
+// truncateString cuts s to maxChars characters, including TruncationMarker.
+// If maxChars leaves no room for the marker, s is simply cut.
+func truncateString(s string, maxChars int) string {
+	runes := []rune(s)
+	if len(runes) <= maxChars {
+		return s
+	}
+	keep := maxChars - utf8.RuneCountInString(TruncationMarker)
+	if keep < 0 {
+		return string(runes[:maxChars])
+	}
+	return string(runes[:keep]) + TruncationMarker
+}
@@ -0,0 +1,142 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTruncateResult(t *testing.T) {
+	apps := make([]string, 100)
+	for i := range apps {
+		apps[i] = fmt.Sprintf("Application %03d", i)
+	}
+	appList, _ := json.Marshal(map[string]interface{}{"success": true, "apps": apps})
+
+	tests := []struct {
+		name     string
+		result   string
+		maxChars int
+		check    func(t *testing.T, got string)
+	}{
+		{
+			name:     "no limit",
+			result:   string(appList),
+			maxChars: 0,
+			check:    wantUnchanged(string(appList)),
+		},
+		{
+			name:     "within limit",
+			result:   `{"success":true}`,
+			maxChars: 100,
+			check:    wantUnchanged(`{"success":true}`),
+		},
+		{
+			name:     "plain text",
+			result:   strings.Repeat("x", 100),
+			maxChars: 50,
+			check: func(t *testing.T, got string) {
+				if !strings.HasSuffix(got, TruncationMarker) {
+					t.Errorf("got %q, want the truncation marker", got)
+				}
+			},
+		},
+		{
+			name:     "screenshot",
+			result:   `{"image_base64":"` + strings.Repeat("A", 500) + `"}`,
+			maxChars: 50,
+			check:    wantUnchanged(`{"image_base64":"` + strings.Repeat("A", 500) + `"}`),
+		},
+		{
+			name:     "array shortened",
+			result:   string(appList),
+			maxChars: 1000,
+			check: func(t *testing.T, got string) {
+				obj := wantJSON(t, got)
+				if obj["truncated"] != true || obj["apps_total"] != float64(100) || obj["success"] != true {
+					t.Errorf("got %v, want truncated apps with apps_total 100", obj)
+				}
+				if n := len(obj["apps"].([]interface{})); n == 0 || n >= 100 {
+					t.Errorf("kept %d apps, want some but not all", n)
+				}
+			},
+		},
+		{
+			name:     "fallback prefix",
+			result:   `{"success":false,"log":"` + strings.Repeat("y", 500) + `"}`,
+			maxChars: 100,
+			check: func(t *testing.T, got string) {
+				obj := wantJSON(t, got)
+				if obj["truncated"] != true || obj["success"] != false {
+					t.Errorf("got %v, want a truncated fallback keeping success", obj)
+				}
+				if !strings.HasPrefix(obj["output"].(string), `{"success":false`) {
+					t.Errorf("output %q is not a prefix of the result", obj["output"])
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := TruncateResult(tt.result, tt.maxChars)
+			if tt.name != "screenshot" && tt.maxChars > 0 && utf8.RuneCountInString(got) > tt.maxChars {
+				t.Errorf("result has %d characters, limit %d", utf8.RuneCountInString(got), tt.maxChars)
+			}
+			tt.check(t, got)
+		})
+	}
+}
+
+// TestTruncateResultEscaping checks that the fallback stays within maxChars
+// even when JSON escaping inflates the kept prefix.
+func TestTruncateResultEscaping(t *testing.T) {
+	// Quotes, HTML characters, and control characters all grow when escaped
+	payloads := map[string]string{
+		"quotes":   strings.Repeat(`"`, 400),
+		"html":     strings.Repeat("<&>", 200),
+		"controls": strings.Repeat("\x01\t\n", 200),
+		"unicode":  strings.Repeat("日本語", 200),
+	}
+	for name, payload := range payloads {
+		result, _ := json.Marshal(map[string]interface{}{"success": true, "text": payload})
+		for _, maxChars := range []int{40, 64, 100, 250, 999} {
+			t.Run(fmt.Sprintf("%s/%d", name, maxChars), func(t *testing.T) {
+				got := TruncateResult(string(result), maxChars)
+				if n := utf8.RuneCountInString(got); n > maxChars {
+					t.Fatalf("result has %d characters, limit %d: %s", n, maxChars, got)
+				}
+				if maxChars >= 64 {
+					// Room for the fallback object, so it must be valid JSON
+					wantJSON(t, got)
+				}
+			})
+		}
+	}
+}
+
+func TestTruncateResultTinyLimit(t *testing.T) {
+	result := `{"success":true,"text":"` + strings.Repeat("z", 100) + `"}`
+	got := TruncateResult(result, 10)
+	if n := utf8.RuneCountInString(got); n > 10 {
+		t.Errorf("result has %d characters, limit 10: %q", n, got)
+	}
+}
+
+func wantUnchanged(want string) func(t *testing.T, got string) {
+	return func(t *testing.T, got string) {
+		if got != want {
+			t.Errorf("result changed: got %.60q", got)
+		}
+	}
+}
+
+func wantJSON(t *testing.T, s string) map[string]interface{} {
+	t.Helper()
+	var obj map[string]interface{}
+	if err := json.Unmarshal([]byte(s), &obj); err != nil {
+		t.Fatalf("result is not a JSON object: %v: %s", err, s)
+	}
+	return obj
+}
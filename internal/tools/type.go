@@ -2,11 +2,41 @@ package tools
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+const (
+	// DefaultTypeChunkSize is the number of characters typed per chunk.
+	// Long text is split into chunks so target apps are not overwhelmed.
+	DefaultTypeChunkSize = 200
+	// DefaultTypeChunkDelayMs is the pause between chunks in milliseconds.
+	DefaultTypeChunkDelayMs = 300
+	// MaxTypeFileSize is the largest file, in bytes, that can be typed via file_path.
+	MaxTypeFileSize = 64 * 1024
+)
+
+// chunkPause waits between typed chunks. It is a variable so chunk pacing can
+// be driven by a fake backend.
+var chunkPause = time.After
+
 // TypeTool types text at the current cursor position.
 type TypeTool struct {
 	BaseTool
+	// KeyboardLayout is the active keyboard layout (default: "us"). On other
+	// layouts, characters that key events may mistype are pasted instead.
+	KeyboardLayout string
+	// FileRoot is the directory file_path must resolve inside. When empty,
+	// typing files is disabled and file_path is not offered to the model.
+	FileRoot string
+	// PasteThreshold is the text length in characters above which text is
+	// pasted through the clipboard instead of typed. Zero (the default) never
+	// pastes text; file contents are always pasted.
+	PasteThreshold int
 }
 
 // NewTypeTool creates a new type tool.
@@ -19,35 +49,92 @@ func (t *TypeTool) Name() string {
 }
 
 func (t *TypeTool) Description() string {
-	return `Type text at the current cursor position. The text is typed character by character to simulate natural typing. Use this to fill in forms, enter commands, or input any text. Make sure the target input field is focused before typing.`
+	desc := `Type text at the current cursor position. The text is typed character by character to simulate natural typing. Use this to fill in forms, enter commands, or input any text. Make sure the target input field is focused before typing. Long text is automatically split into chunks with a short pause between them.`
+	if t.PasteThreshold > 0 {
+		desc += fmt.Sprintf(" Text longer than %d characters is pasted through the clipboard instead.", t.PasteThreshold)
+	}
+	if t.FileRoot != "" {
+		desc += " The contents of a file given via file_path are pasted through the clipboard."
+	}
+	return desc
 }
 
 func (t *TypeTool) Parameters() map[string]ParameterSpec {
-	return map[string]ParameterSpec{
-		"text": {
-			Type:        "string",
-			Description: "The text to type",
-			Required:    true,
-		},
+	textSpec := ParameterSpec{
+		Type:        "string",
+		Description: "The text to type",
+		Required:    true,
+	}
+	if t.FileRoot != "" {
+		textSpec.Description = "The text to type (required unless file_path is given)"
+		textSpec.Required = false
+	}
+	params := map[string]ParameterSpec{
+		"text": textSpec,
 		"delay_ms": {
 			Type:        "integer",
 			Description: "Delay between characters in milliseconds (default: 50 for human-like typing)",
 			Required:    false,
 			Default:     50,
 		},
+		"chunk_size": {
+			Type:        "integer",
+			Description: "Maximum characters typed per chunk for long text (default: 200)",
+			Required:    false,
+			Default:     DefaultTypeChunkSize,
+		},
+		"chunk_delay_ms": {
+			Type:        "integer",
+			Description: "Pause between chunks in milliseconds (default: 300)",
+			Required:    false,
+			Default:     DefaultTypeChunkDelayMs,
+		},
+		"verify": {
+			Type:        "boolean",
+			Description: "After typing, read the field back via select-all and copy and compare it to the text (default: false). Use for important input; the whole field is compared, so only use on fields that were empty.",
+			Required:    false,
+			Default:     false,
+		},
+	}
+	if t.FileRoot != "" {
+		params["file_path"] = ParameterSpec{
+			Type:        "string",
+			Description: "Path of a UTF-8 text file under " + t.FileRoot + " whose contents to paste instead of typing text (max 64 KB). Relative paths are resolved against that directory.",
+			Required:    false,
+		}
 	}
+	return params
 }
 
 func (t *TypeTool) Execute(ctx context.Context, argsJSON string) (string, error) {
 	var args struct {
-		Text    string `json:"text"`
-		DelayMs int    `json:"delay_ms"`
+		Text         string `json:"text"`
+		FilePath     string `json:"file_path"`
+		DelayMs      int    `json:"delay_ms"`
+		ChunkSize    int    `json:"chunk_size"`
+		ChunkDelayMs int    `json:"chunk_delay_ms"`
+		Verify       bool   `json:"verify"`
 	}
 
 	if err := ParseArgs(argsJSON, &args); err != nil {
 		return ErrorResponse("invalid arguments: "+err.Error(), "Provide the text to type"), nil
 	}
 
+	if args.Text != "" && args.FilePath != "" {
+		return ErrorResponse("text and file_path are mutually exclusive", "Provide either text or file_path"), nil
+	}
+
+	if args.FilePath != "" {
+		if t.FileRoot == "" {
+			return ErrorResponse("typing files is disabled", "Provide the text to type instead"), nil
+		}
+		text, err := readTypeFile(t.FileRoot, args.FilePath)
+		if err != nil {
+			return ErrorResponse(err.Error(), "Provide the path of an existing UTF-8 text file up to 64 KB under "+t.FileRoot), nil
+		}
+		args.Text = text
+	}
+
 	if args.Text == "" {
 		return ErrorResponse("text cannot be empty", "Provide the text to type"), nil
 	}
@@ -58,11 +145,173 @@ func (t *TypeTool) Execute(ctx context.Context, argsJSON string) (string, error)
 		charDelay = 50
 	}
 
-	// Platform-specific typing implementation
-	return typeText(ctx, args.Text, charDelay)
+	chunkSize := args.ChunkSize
+	if chunkSize <= 0 {
+		chunkSize = DefaultTypeChunkSize
+	}
+	chunkDelay := args.ChunkDelayMs
+	if chunkDelay <= 0 {
+		chunkDelay = DefaultTypeChunkDelayMs
+	}
+
+	result := map[string]interface{}{
+		"typed_text": args.Text,
+		"char_count": len(args.Text),
+	}
+	if args.FilePath != "" || (t.PasteThreshold > 0 && utf8.RuneCountInString(args.Text) > t.PasteThreshold) {
+		// Files, and long text if configured, are pasted in one go; typing them
+		// key by key is slow and drops characters in many apps
+		if err := pasteSegment(ctx, args.Text); err != nil {
+			return ErrorResponse(
+				"pasting failed: "+err.Error(),
+				"Make sure the application is focused and accepts pasted input",
+			), nil
+		}
+		result["method"] = "paste"
+	} else {
+		chunks := splitTextChunks(args.Text, chunkSize)
+		for i, chunk := range chunks {
+			if i > 0 {
+				// Give the target app time to process the previous chunk
+				select {
+				case <-ctx.Done():
+					return ErrorResponse(
+						fmt.Sprintf("typing cancelled after %d of %d chunks", i, len(chunks)),
+						"",
+					), nil
+				case <-chunkPause(time.Duration(chunkDelay) * time.Millisecond):
+				}
+			}
+
+			if err := t.typeChunk(ctx, chunk, charDelay); err != nil {
+				return ErrorResponse(
+					fmt.Sprintf("typing failed in chunk %d of %d: %v", i+1, len(chunks), err),
+					"Make sure the application is focused and accepts keyboard input",
+				), nil
+			}
+		}
+		result["delay_ms"] = charDelay
+		result["chunks_sent"] = len(chunks)
+		result["method"] = typeMethod
+	}
+
+	if !isUSLayout(t.KeyboardLayout) {
+		result["keyboard_layout"] = t.KeyboardLayout
+	}
+	if args.Verify {
+		fieldText, err := readFocusedField()
+		if err != nil {
+			return ErrorResponse(
+				"typed text but verification failed: "+err.Error(),
+				"Take a screenshot to check the field contents",
+			), nil
+		}
+		verified := normalizeWhitespace(fieldText) == normalizeWhitespace(args.Text)
+		result["verified"] = verified
+		if !verified {
+			result["field_text"] = fieldText
+			result["warning"] = "The field does not contain the intended text. Characters may have been dropped; clear the field and retype."
+		}
+	}
+	if args.FilePath != "" {
+		// Don't echo whole files back into the model's context
+		delete(result, "typed_text")
+		result["file_path"] = args.FilePath
+	}
+	return SuccessResponse(result), nil
 }
 
 // Run implements the interfaces.Tool Run method by delegating to Execute.
 func (t *TypeTool) Run(ctx context.Context, input string) (string, error) {
 	return t.Execute(ctx, input)
 }
+
+// typeChunk types a chunk, pasting the characters that are unreliable as key
+// events on the configured keyboard layout.
+func (t *TypeTool) typeChunk(ctx context.Context, chunk string, charDelay int) error {
+	for _, segment := range splitByLayout(chunk, t.KeyboardLayout) {
+		if segment.paste {
+			if err := pasteSegment(ctx, segment.text); err != nil {
+				return err
+			}
+			continue
+		}
+		// Platform-specific typing implementation
+		if err := typeSegment(ctx, segment.text, charDelay); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readTypeFile reads a UTF-8 text file for typing, enforcing MaxTypeFileSize.
+// path is resolved against root and must stay inside root once symlinks are
+// followed, so the model can't read arbitrary files such as SSH keys.
+func readTypeFile(root, path string) (string, error) {
+	path, err := resolveInRoot(root, path)
+	if err != nil {
+		return "", err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read file: %w", err)
+	}
+	if info.IsDir() {
+		return "", fmt.Errorf("file_path is a directory: %s", path)
+	}
+	if info.Size() > MaxTypeFileSize {
+		return "", fmt.Errorf("file is too large to type: %d bytes (max %d)", info.Size(), MaxTypeFileSize)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read file: %w", err)
+	}
+	if !utf8.Valid(data) {
+		return "", fmt.Errorf("file is not valid UTF-8 text: %s", path)
+	}
+	return string(data), nil
+}
+
+// resolveInRoot resolves path against root, following symlinks, and returns
+// the resolved path if it lies inside root.
+func resolveInRoot(root, path string) (string, error) {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return "", fmt.Errorf("invalid file root: %w", err)
+	}
+	realRoot, err := filepath.EvalSymlinks(absRoot)
+	if err != nil {
+		return "", fmt.Errorf("invalid file root: %w", err)
+	}
+
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(realRoot, path)
+	}
+	realPath, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read file: %w", err)
+	}
+
+	rel, err := filepath.Rel(realRoot, realPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || filepath.IsAbs(rel) {
+		return "", fmt.Errorf("file_path is outside the allowed directory %s", root)
+	}
+	return realPath, nil
+}
+
+// splitTextChunks splits text into chunks of at most size characters.
+// Splitting is rune-aware so multi-byte characters are never broken.
+func splitTextChunks(text string, size int) []string {
+	runes := []rune(text)
+	chunks := make([]string, 0, (len(runes)+size-1)/size)
+	for start := 0; start < len(runes); start += size {
+		end := start + size
+		if end > len(runes) {
+			end = len(runes)
+		}
+		chunks = append(chunks, string(runes[start:end]))
+	}
+	return chunks
+}
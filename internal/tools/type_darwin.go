@@ -4,16 +4,20 @@ package tools
 
 import (
 	"context"
+	"fmt"
 	"math/rand"
 	"os/exec"
 	"time"
 )
 
+// typeMethod identifies the typing backend in tool results.
+const typeMethod = "applescript"
+
 // typeText types text on macOS using AppleScript for reliability with secure input fields.
 // Types CHARACTER BY CHARACTER with human-like delays to appear natural and work reliably.
 // AppleScript's "keystroke" command works with Spotlight, password fields, and other secure inputs
 // where robotgo's TypeStr() fails.
-func typeText(_ context.Context, text string, delayMs int) (string, error) {
+func typeText(_ context.Context, text string, delayMs int) error {
 	// Delay before typing to ensure UI is ready
 	time.Sleep(200 * time.Millisecond)
 
@@ -34,10 +38,7 @@ func typeText(_ context.Context, text string, delayMs int) (string, error) {
 		cmd := exec.Command("osascript", "-e", script)
 		err := cmd.Run()
 		if err != nil {
-			return ErrorResponse(
-				"failed to type character '"+charStr+"': "+err.Error(),
-				"Make sure the application is focused and accepts keyboard input",
-			), nil
+			return fmt.Errorf("failed to type character '%s': %w", charStr, err)
 		}
 
 		// Human-like delay between characters
@@ -57,11 +58,5 @@ func typeText(_ context.Context, text string, delayMs int) (string, error) {
 	// Small delay after typing to let UI catch up
 	time.Sleep(100 * time.Millisecond)
 
-	return SuccessResponse(map[string]interface{}{
-		"typed_text":   text,
-		"char_count":   len(text),
-		"delay_ms":     delayMs,
-		"method":       "applescript",
-		"human_typing": true,
-	}), nil
+	return nil
 }
@@ -0,0 +1,213 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadTypeFile(t *testing.T) {
+	root := t.TempDir()
+	outside := t.TempDir()
+
+	write := func(dir, name, content string) string {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	inside := write(root, "notes.txt", "hello")
+	secret := write(outside, "id_rsa", "secret")
+	if err := os.MkdirAll(filepath.Join(root, "sub"), 0o700); err != nil {
+		t.Fatal(err)
+	}
+	write(filepath.Join(root, "sub"), "nested.txt", "nested")
+	if err := os.Symlink(secret, filepath.Join(root, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+	write(root, "big.txt", strings.Repeat("a", MaxTypeFileSize+1))
+	write(root, "binary.bin", "\xff\xfe")
+
+	tests := []struct {
+		name    string
+		path    string
+		want    string
+		wantErr string
+	}{
+		{"absolute inside root", inside, "hello", ""},
+		{"relative to root", "notes.txt", "hello", ""},
+		{"nested", filepath.Join("sub", "nested.txt"), "nested", ""},
+		{"absolute outside root", secret, "", "outside the allowed directory"},
+		{"dot-dot escape", filepath.Join("..", filepath.Base(outside), "id_rsa"), "", "outside the allowed directory"},
+		{"symlink escape", "link", "", "outside the allowed directory"},
+		{"missing", "missing.txt", "", "cannot read file"},
+		{"directory", "sub", "", "is a directory"},
+		{"too large", "big.txt", "", "too large"},
+		{"not utf-8", "binary.bin", "", "not valid UTF-8"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readTypeFile(root, tt.path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTypeToolFilePathDisabledByDefault(t *testing.T) {
+	tool := NewTypeTool()
+	if _, ok := tool.Parameters()["file_path"]; ok {
+		t.Error("file_path offered without a FileRoot")
+	}
+
+	out, err := tool.Execute(t.Context(), `{"file_path": "/etc/passwd"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "disabled") {
+		t.Errorf("got %s, want file typing to be disabled", out)
+	}
+
+	if !tool.Parameters()["text"].Required {
+		t.Error("text not required without a FileRoot")
+	}
+
+	tool.FileRoot = t.TempDir()
+	if _, ok := tool.Parameters()["file_path"]; !ok {
+		t.Error("file_path not offered with a FileRoot")
+	}
+	if tool.Parameters()["text"].Required {
+		t.Error("text required although file_path can replace it")
+	}
+}
+
+func TestTypeToolPastesFileContents(t *testing.T) {
+	root := t.TempDir()
+	content := "line one\nline two"
+	if err := os.WriteFile(filepath.Join(root, "notes.txt"), []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inserted := fakeTyping(t)
+	tool := NewTypeTool()
+	tool.FileRoot = root
+
+	out, err := tool.Execute(t.Context(), `{"file_path": "notes.txt"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"paste:" + content}; !reflect.DeepEqual(*inserted, want) {
+		t.Errorf("inserted %q, want %q", *inserted, want)
+	}
+	if strings.Contains(out, "line one") {
+		t.Errorf("result %s echoes the file contents", out)
+	}
+}
+
+func TestTypeToolRejectsFileOutsideRoot(t *testing.T) {
+	secret := filepath.Join(t.TempDir(), "id_rsa")
+	if err := os.WriteFile(secret, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	inserted := fakeTyping(t)
+	tool := NewTypeTool()
+	tool.FileRoot = t.TempDir()
+
+	out, err := tool.Execute(t.Context(), `{"file_path": "`+filepath.ToSlash(secret)+`"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "outside the allowed directory") {
+		t.Errorf("got %s, want the path to be rejected", out)
+	}
+	if len(*inserted) != 0 {
+		t.Errorf("inserted %q before rejecting the path", *inserted)
+	}
+}
+
+func TestSplitTextChunks(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		size int
+		want []string
+	}{
+		{"shorter than chunk", "hello", 10, []string{"hello"}},
+		{"exact multiple", "abcdef", 3, []string{"abc", "def"}},
+		{"remainder", "abcdefg", 3, []string{"abc", "def", "g"}},
+		{"one per chunk", "abc", 1, []string{"a", "b", "c"}},
+		{"multi-byte runes kept whole", "héllo wörld", 4, []string{"héll", "o wö", "rld"}},
+		{"emoji", "😀😁😂", 2, []string{"😀😁", "😂"}},
+		{"empty", "", 5, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitTextChunks(tt.text, tt.size)
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") || len(got) != len(tt.want) {
+				t.Errorf("splitTextChunks(%q, %d) = %q, want %q", tt.text, tt.size, got, tt.want)
+			}
+			if strings.Join(got, "") != tt.text {
+				t.Errorf("chunks %q do not reassemble to %q", got, tt.text)
+			}
+		})
+	}
+}
+
+func TestTypeToolChunks(t *testing.T) {
+	inserted := fakeTyping(t)
+	orig := chunkPause
+	t.Cleanup(func() { chunkPause = orig })
+	chunkPause = func(d time.Duration) <-chan time.Time {
+		*inserted = append(*inserted, "pause:"+d.String())
+		ch := make(chan time.Time, 1)
+		ch <- time.Now()
+		return ch
+	}
+
+	out, err := NewTypeTool().Execute(t.Context(), `{"text": "abcdefg", "chunk_size": 3, "chunk_delay_ms": 120}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"type:abc", "pause:120ms", "type:def", "pause:120ms", "type:g"}
+	if !reflect.DeepEqual(*inserted, want) {
+		t.Errorf("inserted %q, want %q", *inserted, want)
+	}
+	if !strings.Contains(out, `"chunks_sent":3`) {
+		t.Errorf("result %s, want 3 chunks sent", out)
+	}
+}
+
+func TestTypeToolRejectsBadInput(t *testing.T) {
+	tests := []struct {
+		name, args, want string
+	}{
+		{"empty text", `{"text": ""}`, "text cannot be empty"},
+		{"text and file", `{"text": "a", "file_path": "b"}`, "mutually exclusive"},
+		{"malformed", `{"text": `, "invalid arguments"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := NewTypeTool().Execute(t.Context(), tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(out, tt.want) {
+				t.Errorf("got %s, want it to contain %q", out, tt.want)
+			}
+		})
+	}
+}
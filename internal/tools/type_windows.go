@@ -9,8 +9,11 @@ import (
 	"github.com/go-vgo/robotgo"
 )
 
+// typeMethod identifies the typing backend in tool results.
+const typeMethod = "robotgo"
+
 // typeText types text on Windows using robotgo.
-func typeText(_ context.Context, text string, delayMs int) (string, error) {
+func typeText(_ context.Context, text string, delayMs int) error {
 	// Delay before typing to ensure UI is ready
 	time.Sleep(150 * time.Millisecond)
 
@@ -22,10 +25,5 @@ func typeText(_ context.Context, text string, delayMs int) (string, error) {
 		}
 	}
 
-	return SuccessResponse(map[string]interface{}{
-		"typed_text": text,
-		"char_count": len(text),
-		"delay_ms":   delayMs,
-		"method":     "robotgo",
-	}), nil
+	return nil
 }
@@ -0,0 +1,97 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ValidateArgs checks JSON arguments against a tool's parameter specs before
+// the tool runs. It reports the first offending field precisely (e.g.,
+// "x: expected integer, got string") so the model can correct its call.
+// Unknown fields are ignored; null values are treated as absent.
+func ValidateArgs(argsJSON string, params map[string]ParameterSpec) error {
+	args := make(map[string]interface{})
+	if argsJSON != "" {
+		if err := json.Unmarshal([]byte(argsJSON), &args); err != nil {
+			return fmt.Errorf("arguments must be a JSON object: %v", err)
+		}
+	}
+
+	// Check in a stable order so errors are deterministic
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		spec := params[name]
+		value, ok := args[name]
+		if !ok || value == nil {
+			if spec.Required {
+				return fmt.Errorf("%s: required parameter is missing", name)
+			}
+			continue
+		}
+
+		if err := checkType(value, spec.Type); err != nil {
+			return fmt.Errorf("%s: %v", name, err)
+		}
+
+		if len(spec.Enum) > 0 && !inEnum(value, spec.Enum) {
+			return fmt.Errorf("%s: value %v is not one of %v", name, value, spec.Enum)
+		}
+	}
+	return nil
+}
+
+// checkType verifies a decoded JSON value against a JSON-schema type name.
+func checkType(value interface{}, want string) error {
+	got := jsonTypeName(value)
+	switch want {
+	case "integer":
+		if f, ok := value.(float64); ok && f == math.Trunc(f) {
+			return nil
+		}
+	case "number":
+		if got == "number" {
+			return nil
+		}
+	case "", got:
+		return nil
+	}
+
+	if want == "integer" && got == "number" {
+		return fmt.Errorf("expected integer, got fractional number %v", value)
+	}
+	return fmt.Errorf("expected %s, got %s", want, got)
+}
+
+// jsonTypeName returns the JSON type name of a value decoded by encoding/json.
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case []interface{}:
+		return "array"
+	case map[string]interface{}:
+		return "object"
+	default:
+		return "null"
+	}
+}
+
+func inEnum(value interface{}, enum []interface{}) bool {
+	for _, e := range enum {
+		if e == value {
+			return true
+		}
+	}
+	return false
+}
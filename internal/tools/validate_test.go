@@ -0,0 +1,64 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateArgs(t *testing.T) {
+	params := map[string]ParameterSpec{
+		"x":         {Type: "integer", Required: true},
+		"y":         {Type: "integer", Required: true},
+		"button":    {Type: "string", Enum: []interface{}{"left", "right", "middle"}},
+		"double":    {Type: "boolean"},
+		"amount":    {Type: "number"},
+		"keys":      {Type: "array"},
+		"options":   {Type: "object"},
+		"untyped":   {},
+		"direction": {Type: "string"},
+	}
+
+	tests := []struct {
+		name    string
+		args    string
+		wantErr string // "" means valid
+	}{
+		{"minimal", `{"x": 10, "y": 20}`, ""},
+		{"all fields", `{"x": 1, "y": 2, "button": "right", "double": true, "amount": 2.5, "keys": ["a"], "options": {}, "untyped": "anything"}`, ""},
+		{"integer as whole float", `{"x": 10.0, "y": 20}`, ""},
+		{"number accepts integer", `{"x": 1, "y": 2, "amount": 3}`, ""},
+		{"unknown fields ignored", `{"x": 1, "y": 2, "extra": "ignored"}`, ""},
+		{"null optional", `{"x": 1, "y": 2, "button": null}`, ""},
+		{"missing required", `{"x": 10}`, "y: required parameter is missing"},
+		{"null required", `{"x": null, "y": 1}`, "x: required parameter is missing"},
+		{"empty args", ``, "x: required parameter is missing"},
+		{"string for integer", `{"x": "10", "y": 20}`, "x: expected integer, got string"},
+		{"fractional integer", `{"x": 10.5, "y": 20}`, "x: expected integer, got fractional number 10.5"},
+		{"string for boolean", `{"x": 1, "y": 2, "double": "yes"}`, "double: expected boolean, got string"},
+		{"object for array", `{"x": 1, "y": 2, "keys": {}}`, "keys: expected array, got object"},
+		{"enum violation", `{"x": 1, "y": 2, "button": "side"}`, "button: value side is not one of"},
+		{"not an object", `[1, 2]`, "arguments must be a JSON object"},
+		{"malformed", `{"x": `, "arguments must be a JSON object"},
+		{"first error in name order", `{"direction": 5}`, "direction: expected string, got number"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateArgs(tt.args, params)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateArgsNoParams(t *testing.T) {
+	if err := ValidateArgs(`{"anything": 1}`, nil); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
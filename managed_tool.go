@@ -0,0 +1,159 @@
+package cua
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+
+	"github.com/anxuanzi/cua/internal/tools"
+)
+
+// managedTool wraps a CUA tool so every call, whether issued by the model or
+// through ExecuteTool, passes through the agent's per-call policies.
+type managedTool struct {
+	interfaces.Tool
+	cua *CUA
+}
+
+// Execute applies the agent's policies and then executes the wrapped tool.
+func (t *managedTool) Execute(ctx context.Context, argsJSON string) (string, error) {
+	tracer := t.cua.config.Tracer
+	if tracer == nil {
+		result, _, err := t.cua.executeToolSafely(ctx, t.Tool, argsJSON)
+		return result, err
+	}
+
+	// Each tool call becomes a child span of the run
+	ctx, span := tracer.StartSpan(ctx, "tool."+t.Name())
+	defer span.End()
+	start := time.Now()
+
+	result, panicErr, err := t.cua.executeToolSafely(ctx, t.Tool, argsJSON)
+
+	span.SetAttribute("tool.name", t.Name())
+	span.SetAttribute("tool.success", toolSucceeded(result, err))
+	span.SetAttribute("duration_ms", time.Since(start).Milliseconds())
+	if panicErr != nil {
+		span.SetAttribute("tool.panic_stack", string(panicErr.Stack))
+		span.RecordError(panicErr)
+	}
+	if err != nil {
+		span.RecordError(err)
+	}
+	return result, err
+}
+
+// Run implements the interfaces.Tool Run method by delegating to Execute.
+func (t *managedTool) Run(ctx context.Context, input string) (string, error) {
+	return t.Execute(ctx, input)
+}
+
+// wrapTools wraps each tool with the agent's per-call policies.
+func (c *CUA) wrapTools(toolList []interfaces.Tool) []interfaces.Tool {
+	wrapped := make([]interfaces.Tool, len(toolList))
+	for i, t := range toolList {
+		wrapped[i] = &managedTool{Tool: t, cua: c}
+	}
+	return wrapped
+}
+
+// executeTool runs a single tool call. Policy failures are returned as error
+// observations (not Go errors) so the model can adapt, matching the tools' own
+// error convention.
+func (c *CUA) executeTool(ctx context.Context, tool interfaces.Tool, argsJSON string) (string, error) {
+	// Hold the action while the agent is paused; a stop cancels the wait
+	if err := c.control.waitIfPaused(ctx); err != nil {
+		return "", err
+	}
+
+	if err := c.checkGuardrails(tool.Name()); err != nil {
+		return tools.ErrorResponse(
+			"action blocked by guardrail: "+err.Error(),
+			"Do not retry. Report that the task cannot continue right now.",
+		), nil
+	}
+
+	if c.config.ActionFilter != nil {
+		filtered, allowed, err := applyActionFilter(c.config.ActionFilter, tool.Name(), argsJSON)
+		if err != nil {
+			return tools.ErrorResponse("action filter returned invalid arguments: "+err.Error(), ""), nil
+		}
+		if !allowed {
+			return tools.ErrorResponse(
+				"action denied by filter: "+tool.Name(),
+				"This action is not permitted. Choose a different approach.",
+			), nil
+		}
+		argsJSON = filtered
+	}
+
+	if c.config.ToolConfirmation != nil {
+		confirm := func(action string, args map[string]any) (FilterDecision, map[string]any) {
+			return c.config.ToolConfirmation(ctx, action, args)
+		}
+		confirmed, allowed, err := applyActionFilter(confirm, tool.Name(), argsJSON)
+		if err != nil {
+			return tools.ErrorResponse("tool confirmation returned invalid arguments: "+err.Error(), ""), nil
+		}
+		if !allowed {
+			return tools.ErrorResponse(
+				"action declined by the user: "+tool.Name(),
+				"The user did not approve this action. Choose a different approach or ask the user.",
+			), nil
+		}
+		argsJSON = confirmed
+	}
+
+	// Reject malformed arguments with a precise, field-level error
+	if err := tools.ValidateArgs(argsJSON, tool.Parameters()); err != nil {
+		return tools.ErrorResponse(
+			"invalid arguments for "+tool.Name()+": "+err.Error(),
+			"Check the parameter types and required fields, then retry",
+		), nil
+	}
+
+	// Count only calls that actually run, not denied or invalid ones
+	if err := c.recordToolCall(ctx); err != nil {
+		stopRun(ctx, err)
+		return tools.ErrorResponse(err.Error(), "The run is being stopped."), nil
+	}
+	c.recordRateLimitedCall(tool.Name())
+
+	result, err := tool.Execute(ctx, argsJSON)
+	if tool.Name() == tools.HelpToolName && toolSucceeded(result, err) {
+		// Hand the task to a human rather than letting the model carry on
+		var args struct {
+			Reason string `json:"reason"`
+		}
+		_ = json.Unmarshal([]byte(argsJSON), &args)
+		stopRun(ctx, &NeedsHelpError{Reason: args.Reason})
+	}
+	if tool.Name() != "screen_capture" {
+		// Any other tool may change the screen, so don't serve a throttled frame after it
+		tools.InvalidateCaptures(ctx)
+	}
+	if err != nil {
+		return result, err
+	}
+
+	// Keep oversized results from bloating the model's context
+	return tools.TruncateResult(result, c.config.ToolResultMaxChars), nil
+}
+
+// toolSucceeded reports whether a tool call succeeded. Tools report failures
+// as {"success": false, ...} observations; results without a success field
+// (such as screenshots) count as successful.
+func toolSucceeded(result string, err error) bool {
+	if err != nil {
+		return false
+	}
+	var status struct {
+		Success *bool `json:"success"`
+	}
+	if json.Unmarshal([]byte(result), &status) != nil || status.Success == nil {
+		return true
+	}
+	return *status.Success
+}
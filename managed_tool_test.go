@@ -0,0 +1,128 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// fakeSpan records the attributes and errors reported on a span.
+type fakeSpan struct {
+	name  string
+	attrs map[string]interface{}
+	errs  []error
+	ended bool
+}
+
+func (s *fakeSpan) End()                                    { s.ended = true }
+func (s *fakeSpan) AddEvent(string, map[string]interface{}) {}
+func (s *fakeSpan) SetAttribute(key string, value interface{}) {
+	s.attrs[key] = value
+}
+func (s *fakeSpan) RecordError(err error) { s.errs = append(s.errs, err) }
+
+// fakeTracer records every span it starts.
+type fakeTracer struct {
+	mu    sync.Mutex
+	spans []*fakeSpan
+}
+
+func (tr *fakeTracer) StartSpan(ctx context.Context, name string) (context.Context, interfaces.Span) {
+	tr.mu.Lock()
+	defer tr.mu.Unlock()
+	span := &fakeSpan{name: name, attrs: make(map[string]interface{})}
+	tr.spans = append(tr.spans, span)
+	return ctx, span
+}
+
+func (tr *fakeTracer) StartTraceSession(ctx context.Context, _ string) (context.Context, interfaces.Span) {
+	return tr.StartSpan(ctx, "session")
+}
+
+func TestToolSucceeded(t *testing.T) {
+	tests := []struct {
+		name   string
+		result string
+		err    error
+		want   bool
+	}{
+		{"success true", `{"success":true}`, nil, true},
+		{"success false", `{"success":false,"error":"boom"}`, nil, false},
+		{"no success field", `{"width":1920}`, nil, true},
+		{"not json", `plain text`, nil, true},
+		{"go error", `{"success":true}`, errors.New("boom"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toolSucceeded(tt.result, tt.err); got != tt.want {
+				t.Errorf("toolSucceeded = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestManagedToolSpans(t *testing.T) {
+	tracer := &fakeTracer{}
+	cfg := defaultConfig()
+	WithTracer(tracer)(cfg)
+	c := newTestCUA(cfg)
+
+	wrapped := c.wrapTools([]interfaces.Tool{
+		&fakeTool{name: "mouse_click"},
+		&fakeTool{name: "keyboard_type", result: `{"success":false,"error":"no focus"}`},
+		&fakeTool{name: "app_launch", panics: "boom"},
+	})
+	for _, tool := range wrapped {
+		_, _ = tool.Execute(context.Background(), "{}")
+	}
+
+	want := []struct {
+		name    string
+		success bool
+		errs    int
+	}{
+		{"tool.mouse_click", true, 0},
+		{"tool.keyboard_type", false, 0},
+		{"tool.app_launch", false, 1},
+	}
+	if len(tracer.spans) != len(want) {
+		t.Fatalf("got %d spans, want %d", len(tracer.spans), len(want))
+	}
+	for i, w := range want {
+		span := tracer.spans[i]
+		if span.name != w.name {
+			t.Errorf("span %d name = %q, want %q", i, span.name, w.name)
+		}
+		if got := span.attrs["tool.success"]; got != w.success {
+			t.Errorf("%s: tool.success = %v, want %v", w.name, got, w.success)
+		}
+		if _, ok := span.attrs["duration_ms"]; !ok {
+			t.Errorf("%s: missing duration_ms", w.name)
+		}
+		if len(span.errs) != w.errs {
+			t.Errorf("%s: recorded %d errors, want %d", w.name, len(span.errs), w.errs)
+		}
+		if !span.ended {
+			t.Errorf("%s: span not ended", w.name)
+		}
+	}
+	if _, ok := tracer.spans[2].attrs["tool.panic_stack"]; !ok {
+		t.Error("panicking tool span has no tool.panic_stack")
+	}
+}
+
+func TestManagedToolWithoutTracer(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	tool := &fakeTool{name: "mouse_click"}
+
+	result, err := c.wrapTools([]interfaces.Tool{tool})[0].Execute(context.Background(), "{}")
+	if err != nil || result != `{"success":true}` {
+		t.Errorf("Execute = (%s, %v), want a plain success", result, err)
+	}
+	if n := tool.calls.Load(); n != 1 {
+		t.Errorf("tool ran %d times, want 1", n)
+	}
+}
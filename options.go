@@ -1,5 +1,13 @@
 package cua
 
+import (
+	"image"
+	"io"
+	"time"
+
+	"github.com/anxuanzi/cua/internal/tools"
+)
+
 // Option is a functional option for configuring the CUA agent.
 type Option func(*Config)
 
@@ -31,6 +39,22 @@ func WithScreenIndex(index int) Option {
 	}
 }
 
+// WithVision declares whether the model supports image input (default: true).
+// Many local models lack vision; disabling it replaces the screenshot-first
+// system prompt with a keyboard-first, text-only variant and removes the
+// screen_capture tool.
+func WithVision(enabled bool) Option {
+	return func(c *Config) {
+		c.Vision = enabled
+	}
+}
+
+// WithVisionDisabled runs the agent without screenshots, for privacy-sensitive
+// environments or non-vision models. It is shorthand for WithVision(false).
+func WithVisionDisabled() Option {
+	return WithVision(false)
+}
+
 // WithReasoning enables or disables extended thinking mode.
 func WithReasoning(enabled bool) Option {
 	return func(c *Config) {
@@ -73,11 +97,27 @@ func WithConversationID(conversationID string) Option {
 	}
 }
 
+// WithModelFallback retries a run that fails with an error (e.g., a provider
+// error or a panic) on the next model in models (same provider), e.g.
+// escalating from a Flash model to a Pro model. Each fallback starts a fresh
+// session; the task is re-sent with a note about the previous attempt's
+// outcome and last response. Runs that end without error are never retried,
+// since they may already have acted on the desktop. At most len(models)
+// escalations happen per run. Runs that were cancelled, timed out, hit the
+// WithMaxActions limit, or asked for help are not retried.
+// Usage from every attempt is tracked.
+func WithModelFallback(models ...string) Option {
+	return func(c *Config) {
+		c.FallbackModels = models
+	}
+}
+
 // WithBaseURL sets a custom API endpoint URL.
 // This allows using custom/proxy endpoints or alternative deployments.
 // For Gemini: overrides the default https://generativelanguage.googleapis.com/
 // For OpenAI: overrides the default https://api.openai.com/v1
 // For Anthropic: overrides the default https://api.anthropic.com
+// For Local: overrides the default http://localhost:11434/v1 (Ollama)
 func WithBaseURL(baseURL string) Option {
 	return func(c *Config) {
 		c.BaseURL = baseURL
@@ -102,3 +142,355 @@ func WithTokenLimitWarning(threshold int, callback TokenLimitCallback) Option {
 		c.OnTokenLimitWarning = callback
 	}
 }
+
+// WithToolCallBudgetWarning warns when a run nears a budget of tool calls, so
+// UIs can tell users a task is taking more steps than expected. budget is the
+// number of tool calls a run is expected to stay within; threshold is the
+// percentage (0-100) of it at which callback fires (default: 80). Only calls
+// that actually execute count - calls blocked by guardrails, filters, or
+// argument validation don't. The budget is not enforced, and it counts tool
+// calls rather than LLM iterations (MaxIterations), since one iteration may
+// run several tools. callback is called once per run, from the tool-calling
+// goroutine.
+func WithToolCallBudgetWarning(budget, threshold int, callback ToolCallBudgetCallback) Option {
+	return func(c *Config) {
+		c.ToolCallBudget = budget
+		c.ToolCallBudgetWarningThreshold = threshold
+		c.OnToolCallBudgetWarning = callback
+	}
+}
+
+// WithMaxActions stops a run with ErrMaxActions when it tries to execute more
+// than n tool calls, so a model stuck in a loop can't act indefinitely. Like
+// WithToolCallBudgetWarning, only calls that actually execute count. Unlike
+// MaxIterations, it counts tool calls rather than LLM iterations. 0 (the
+// default) is unlimited.
+func WithMaxActions(n int) Option {
+	return func(c *Config) {
+		c.MaxActions = n
+	}
+}
+
+// WithHelpRequests offers the model a request_help tool for when it cannot
+// continue on its own (e.g., a CAPTCHA or missing credentials). Calling it
+// stops the run with a *NeedsHelpError carrying the model's reason, and the
+// Result's Reason is ReasonNeedsHelp.
+func WithHelpRequests(enabled bool) Option {
+	return func(c *Config) {
+		c.HelpRequests = enabled
+	}
+}
+
+// WithQueueing enables or disables serialized run execution.
+// When enabled, concurrent Run, RunDetailed, and RunStream calls are queued and
+// executed one at a time in submission order, each caller receiving its own result.
+// A queued call that is cancelled before it starts returns the context error.
+//
+// Without queueing, concurrent runs execute in parallel. Each run has its own
+// conversation, tool call count, screenshot dedup and throttle caches, and last
+// pointer action (used by focus captures). Runs share the desktop and its input
+// devices, and the agent's tool settings such as the screen index.
+func WithQueueing(enabled bool) Option {
+	return func(c *Config) {
+		c.Queueing = enabled
+	}
+}
+
+// WithActionFilter sets a filter that is invoked before every tool execution.
+// The filter can allow the action, deny it (the model receives an error
+// observation), or rewrite its arguments - e.g., to block clicks in a screen
+// region or to redact text before it is typed.
+func WithActionFilter(filter ActionFilter) Option {
+	return func(c *Config) {
+		c.ActionFilter = filter
+	}
+}
+
+// WithToolConfirmationPrompt turns a run into a supervised step-through: before
+// each action (not observations such as screenshots), the user is asked on out
+// to approve, deny, or always allow that tool, and the answer is read from in
+// (typically os.Stdin and os.Stderr). Denied actions are reported to the model
+// as declined. It runs after any WithActionFilter filter. See
+// NewConfirmationPrompt for the accepted answers. A call waiting for an answer
+// is denied when its run is cancelled or stopped.
+func WithToolConfirmationPrompt(in io.Reader, out io.Writer) Option {
+	return func(c *Config) {
+		c.ToolConfirmation = NewConfirmationPrompt(in, out)
+	}
+}
+
+// WithScreenshotHook sets a hook that post-processes every screenshot before encoding.
+// Use it to watermark, timestamp, redact, log, or upload captures.
+func WithScreenshotHook(hook ScreenshotHook) Option {
+	return func(c *Config) {
+		c.ScreenshotHook = hook
+	}
+}
+
+// WithTracer enables tracing for external observability (e.g., OpenTelemetry).
+// Each run, LLM call, and tool call becomes a span; tool spans are named
+// "tool.<name>" and carry tool.name, tool.success, and duration_ms attributes.
+// Wrap an OpenTelemetry tracer with agent-sdk-go's tracing.NewOTelTracerWrapper.
+func WithTracer(tracer Tracer) Option {
+	return func(c *Config) {
+		c.Tracer = tracer
+	}
+}
+
+// WithDangerousKeyBlocking makes keyboard_press reject destructive key combinations
+// such as Cmd+Q, Alt+F4, or Cmd+Shift+Delete. If no combos are given, a default
+// blocklist is used. Combos are matched regardless of modifier order or aliases.
+// confirm, if non-nil, is asked to approve a blocked combo; otherwise it is rejected.
+func WithDangerousKeyBlocking(confirm func(combo string) bool, combos ...string) Option {
+	return func(c *Config) {
+		if len(combos) == 0 {
+			combos = tools.DefaultDangerousKeyCombos
+		}
+		c.BlockedKeyCombos = combos
+		c.ConfirmKeyCombo = confirm
+	}
+}
+
+// WithControlServer starts an HTTP control plane on addr (e.g., "127.0.0.1:8765")
+// so operators can check status, pause, resume, or stop runs and view the latest
+// screenshot remotely. Every request must send "Authorization: Bearer <token>".
+// See CUA.ControlHandler for the endpoints.
+func WithControlServer(addr, token string) Option {
+	return func(c *Config) {
+		c.ControlServerAddr = addr
+		c.ControlServerToken = token
+	}
+}
+
+// WithResultStore persists the result of every Run/RunDetailed call, including
+// failed runs, to store. Use NewJSONLResultStore for a file-backed store.
+// Save errors are ignored so a failing store never fails a run. If store
+// implements io.Closer, CUA.Close closes it.
+func WithResultStore(store ResultStore) Option {
+	return func(c *Config) {
+		c.ResultStore = store
+	}
+}
+
+// WithShowCursor draws the mouse pointer onto screenshots sent to the model.
+// Screen captures normally omit the hardware cursor, so enabling this helps the
+// model see where the pointer is, e.g. after a move or while hovering.
+func WithShowCursor(show bool) Option {
+	return func(c *Config) {
+		c.ShowCursor = show
+	}
+}
+
+// WithCaptureOnFailure captures a final screenshot when a run fails and attaches
+// it to the run's Result (see LastResult and WithResultStore) as FailureScreenshot,
+// so the exact failure state can be inspected after the fact.
+func WithCaptureOnFailure(enabled bool) Option {
+	return func(c *Config) {
+		c.CaptureOnFailure = enabled
+	}
+}
+
+// WithCoordSnap rounds the screen coordinates of clicks, moves, drags, and
+// scrolls to the nearest multiple of px pixels, so repeated actions on the same
+// target land on exactly the same pixel. Snapped points stay on the screen.
+// 0 (the default) disables snapping.
+func WithCoordSnap(px int) Option {
+	return func(c *Config) {
+		c.CoordSnap = px
+	}
+}
+
+// WithScreenshotJPEGQuality sets the JPEG quality (1-100) of screenshots sent
+// to the model. Lower values make smaller images that cost fewer tokens to
+// upload but blur small text; the default is 65. Values outside 1-100 are
+// ignored.
+func WithScreenshotJPEGQuality(quality int) Option {
+	return func(c *Config) {
+		if quality >= 1 && quality <= 100 {
+			c.ScreenshotJPEGQuality = quality
+		}
+	}
+}
+
+// WithScreenshotGrayscale converts screenshots to grayscale before they are
+// encoded for the model. Text-heavy UIs read the same without color and the
+// images are smaller, but cues carried only by color (red error borders,
+// green status dots, highlighted selections) are lost.
+func WithScreenshotGrayscale(enabled bool) Option {
+	return func(c *Config) {
+		c.ScreenshotGrayscale = enabled
+	}
+}
+
+// WithScreenshotPalette quantizes screenshots to at most n colors (n gray
+// levels when combined with WithScreenshotGrayscale; color screenshots keep
+// at least 8) before they are encoded.
+// Fewer colors compress better, but gradients band and faint details such as
+// subtle borders or disabled text can vanish; values below 16 are rarely
+// worth the accuracy loss. 0 disables quantization.
+func WithScreenshotPalette(n int) Option {
+	return func(c *Config) {
+		c.ScreenshotPalette = n
+	}
+}
+
+// WithScreenshotDedup makes screen_capture return a short "unchanged" result,
+// instead of the full image, when the capture is pixel-identical to the
+// previous screenshot sent in the same run - typically after an action that
+// had no visible effect. This saves the tokens of a duplicate image.
+func WithScreenshotDedup(enabled bool) Option {
+	return func(c *Config) {
+		c.ScreenshotDedup = enabled
+	}
+}
+
+// WithScreenshotThrottle enforces a minimum interval between screen captures.
+// A screen_capture call with the same arguments within minInterval of the
+// previous capture in the same run returns that capture again, with
+// "throttled": true, instead of capturing. Any other tool call in between
+// (a click, keystroke, etc.) clears the cached capture, so the model always
+// sees the effect of its actions. This bounds capture frequency if the model
+// loops on screenshots, without changing agent logic. 0 disables throttling.
+func WithScreenshotThrottle(minInterval time.Duration) Option {
+	return func(c *Config) {
+		c.ScreenshotThrottle = minInterval
+	}
+}
+
+// WithDefaultCaptureRegion crops every screenshot to a fixed region of the
+// screen, given in 0-1000 normalized coordinates (x, y of the top-left corner,
+// then width and height) - e.g., the area an automated app always occupies.
+// Smaller images cost fewer tokens. The region becomes the model's whole
+// working area: the system prompt describes it, and the 0-1000 coordinates of
+// clicks, moves, drags, and scrolls are relative to it, so positions read off
+// a cropped screenshot stay correct. Nothing outside the region can be seen
+// or clicked. Focus captures zoom into part of the region. Without vision
+// (WithVision(false)) the region is ignored.
+func WithDefaultCaptureRegion(x, y, width, height int) Option {
+	return func(c *Config) {
+		region := image.Rect(x, y, x+width, y+height)
+		c.DefaultCaptureRegion = &region
+	}
+}
+
+// WithKeyboardLayout sets the active keyboard layout (e.g., "de", "fr", "uk").
+// Key events assume a US layout, so on other layouts keyboard_type pastes
+// characters that sit on different keys through the clipboard instead of
+// typing them, restoring the clipboard afterwards. On the known layouts "uk",
+// "de", "ch", "fr", and "be", letters and digits on the same keys as on a US
+// layout are still typed (so y and z are pasted on "de", and a, q, w, z, m,
+// and all digits on "fr"); on other layouts everything but spaces is pasted.
+func WithKeyboardLayout(layout string) Option {
+	return func(c *Config) {
+		c.KeyboardLayout = layout
+	}
+}
+
+// WithToolResultMaxChars caps tool results at n characters before they are fed
+// back to the model, so large outputs (e.g., long app lists) don't bloat the
+// context. JSON results stay parseable: arrays are shortened and marked with
+// "truncated": true. Screenshots are never truncated.
+func WithToolResultMaxChars(n int) Option {
+	return func(c *Config) {
+		c.ToolResultMaxChars = n
+	}
+}
+
+// WithRequirePlanApproval gates every run on approval of the model's plan.
+// Before executing a task, the agent asks the model for its intended steps (see
+// ExplainPlan) and passes them to approve; if approve returns false the run
+// returns ErrPlanRejected without taking any action. This costs one extra LLM
+// call per run, which is included in the run's Result and in Usage.
+func WithRequirePlanApproval(approve PlanApprovalFunc) Option {
+	return func(c *Config) {
+		c.PlanApproval = approve
+	}
+}
+
+// WithScreenshotScaler selects how screenshots are downscaled before they are
+// sent to the model. The default, ScalerBiLinear, is fast on large 4K/5K
+// captures; ScalerCatmullRom is sharper but slower, and ScalerNearestNeighbor
+// is fastest but can make small text unreadable.
+func WithScreenshotScaler(scaler ScreenshotScaler) Option {
+	return func(c *Config) {
+		c.ScreenshotScaler = scaler
+	}
+}
+
+// WithOperatingHours only allows actions during a daily local-time window, for
+// unattended automation on shared machines. start and end are offsets from
+// midnight (e.g., 9*time.Hour and 17*time.Hour+30*time.Minute); an end before
+// start spans midnight. Observation tools such as screen_capture still run.
+func WithOperatingHours(start, end time.Duration) Option {
+	return func(c *Config) {
+		c.OperatingHours = &OperatingHours{Start: start, End: end}
+	}
+}
+
+// WithBlockOnLockScreen blocks actions while the screen appears locked or shows
+// a screensaver, detected as a near-uniform capture of the screen the tools
+// act on. The verdict is reused for actions within 2 seconds of a check, so
+// bursts of actions don't each capture the screen.
+func WithBlockOnLockScreen(enabled bool) Option {
+	return func(c *Config) {
+		c.BlockOnLockScreen = enabled
+	}
+}
+
+// WithActionRateLimit limits each action tool (clicks, typing, key presses,
+// etc.) to perMinute calls in any one-minute window, counted across runs.
+// Calls over the limit are blocked and reported to the model. Observation
+// tools such as screen_capture are not limited unless listed in
+// WithActionRateLimits. 0 disables the default limit.
+func WithActionRateLimit(perMinute int) Option {
+	return func(c *Config) {
+		c.ActionRateLimit = perMinute
+	}
+}
+
+// WithActionRateLimits sets per-tool limits, in calls per minute, that override
+// the WithActionRateLimit default - e.g., allow frequent screenshots but
+// throttle key presses:
+//
+//	cua.WithActionRateLimits(map[string]int{"screen_capture": 120, "keyboard_press": 10})
+//
+// A limit of 0 exempts that tool from the default.
+func WithActionRateLimits(limits map[string]int) Option {
+	return func(c *Config) {
+		c.ActionRateLimits = limits
+	}
+}
+
+// WithTaskTemplate registers a reusable task under name. The template may
+// contain {param} placeholders, e.g. "Search {site} for {query}", which are
+// filled in by RunTemplate. Registering a name again replaces the template.
+func WithTaskTemplate(name, template string) Option {
+	return func(c *Config) {
+		if c.TaskTemplates == nil {
+			c.TaskTemplates = make(map[string]string)
+		}
+		c.TaskTemplates[name] = template
+	}
+}
+
+// WithTypeFileRoot lets keyboard_type paste the contents of UTF-8 text files
+// under dir via its file_path parameter. Paths are resolved with symlinks
+// followed and rejected if they end up outside dir. File typing is disabled
+// unless this option is set, so a misled model can't read arbitrary files
+// such as credentials and type them into a form.
+func WithTypeFileRoot(dir string) Option {
+	return func(c *Config) {
+		c.TypeFileRoot = dir
+	}
+}
+
+// WithPasteThreshold makes keyboard_type paste text longer than n characters
+// through the clipboard instead of typing it key by key, which is faster and
+// drops fewer characters in some apps. The clipboard is restored afterwards,
+// but pasting fails in password fields that refuse pasted input. By default
+// (n = 0) text is always typed.
+func WithPasteThreshold(n int) Option {
+	return func(c *Config) {
+		c.PasteThreshold = n
+	}
+}
@@ -0,0 +1,23 @@
+package cua
+
+import "testing"
+
+func TestWithScreenshotJPEGQuality(t *testing.T) {
+	tests := []struct {
+		quality, want int
+	}{
+		{80, 80},
+		{1, 1},
+		{100, 100},
+		{0, 0},
+		{101, 0},
+		{-5, 0},
+	}
+	for _, tt := range tests {
+		cfg := defaultConfig()
+		WithScreenshotJPEGQuality(tt.quality)(cfg)
+		if cfg.ScreenshotJPEGQuality != tt.want {
+			t.Errorf("WithScreenshotJPEGQuality(%d) set %d, want %d", tt.quality, cfg.ScreenshotJPEGQuality, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ package screen
 import (
 	"bytes"
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
@@ -19,17 +20,30 @@ type CaptureResult struct {
 	ScreenIndex    int         // Screen index captured from
 }
 
+// ErrInvalidDisplay is returned when a capture names a display that is not
+// connected. It is permanent: the capture is not retried.
+var ErrInvalidDisplay = errors.New("display index out of range")
+
+// numDisplays returns the number of connected displays.
+// It is a variable so captures can be validated against a fake backend.
+var numDisplays = NumDisplays
+
 // Capture takes a screenshot of the specified screen.
 // If screenIndex is -1, captures the primary screen.
 func Capture(screenIndex int) (*CaptureResult, error) {
+	// An unknown display won't appear on retry
+	if n := numDisplays(); screenIndex >= n {
+		return nil, fmt.Errorf("%w: screen %d of %d", ErrInvalidDisplay, screenIndex, n)
+	}
+
 	// Set display for multi-monitor support
 	if screenIndex >= 0 {
 		robotgo.DisplayID = screenIndex
 		defer func() { robotgo.DisplayID = -1 }()
 	}
 
-	// Capture the screen
-	img, err := robotgo.CaptureImg()
+	// Capture the screen, retrying transient failures
+	img, err := CaptureWithRetry(func() (image.Image, error) { return robotgo.CaptureImg() })
 	if err != nil {
 		return nil, fmt.Errorf("failed to capture screen: %w", err)
 	}
@@ -45,7 +59,12 @@ func Capture(screenIndex int) (*CaptureResult, error) {
 
 // CaptureRegion takes a screenshot of a specific region.
 func CaptureRegion(x, y, width, height int) (*CaptureResult, error) {
-	img, err := robotgo.CaptureImg(x, y, width, height)
+	if width <= 0 || height <= 0 {
+		// Invalid input won't succeed on retry
+		return nil, fmt.Errorf("invalid capture region size %dx%d", width, height)
+	}
+
+	img, err := CaptureWithRetry(func() (image.Image, error) { return robotgo.CaptureImg(x, y, width, height) })
 	if err != nil {
 		return nil, fmt.Errorf("failed to capture region at (%d, %d) size %dx%d: %w", x, y, width, height, err)
 	}
@@ -82,13 +101,13 @@ func CaptureAndProcess(screenIndex int, maxWidth, maxHeight int) (*ProcessedScre
 	resized, scaledW, scaledH := Resize(result.Image, maxWidth, maxHeight)
 
 	// Encode to PNG
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, resized); err != nil {
-		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	data, err := EncodePNG(resized, PNGCompression())
+	if err != nil {
+		return nil, err
 	}
 
 	// Base64 encode
-	b64 := base64.StdEncoding.EncodeToString(buf.Bytes())
+	b64 := base64.StdEncoding.EncodeToString(data)
 
 	return &ProcessedScreenshot{
 		Base64:         b64,
@@ -102,11 +121,11 @@ func CaptureAndProcess(screenIndex int, maxWidth, maxHeight int) (*ProcessedScre
 
 // EncodeToBase64PNG encodes an image to base64 PNG format.
 func EncodeToBase64PNG(img image.Image) (string, error) {
-	var buf bytes.Buffer
-	if err := png.Encode(&buf, img); err != nil {
-		return "", fmt.Errorf("failed to encode PNG: %w", err)
+	data, err := EncodePNG(img, PNGCompression())
+	if err != nil {
+		return "", err
 	}
-	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+	return base64.StdEncoding.EncodeToString(data), nil
 }
 
 // DecodeFromBase64PNG decodes a base64 PNG string to an image.
@@ -0,0 +1,46 @@
+package screen
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/anxuanzi/cua/internal/coords"
+	"golang.org/x/image/draw"
+)
+
+// CropNormalized returns a copy of the region of img given in normalized
+// 0-1000 coordinates relative to the image: (nx, ny) is the top-left corner
+// and nw x nh the size. Values outside 0-1000 are clamped to the image bounds.
+// It is handy for "zoom into this area" flows driven by model coordinates.
+func CropNormalized(img *image.RGBA, nx, ny, nw, nh int) (*image.RGBA, error) {
+	b := img.Bounds()
+	x0 := clampNormalized(nx)
+	y0 := clampNormalized(ny)
+	x1 := clampNormalized(nx + nw)
+	y1 := clampNormalized(ny + nh)
+
+	rect := image.Rect(
+		b.Min.X+x0*b.Dx()/coords.NormalizedMax,
+		b.Min.Y+y0*b.Dy()/coords.NormalizedMax,
+		b.Min.X+x1*b.Dx()/coords.NormalizedMax,
+		b.Min.Y+y1*b.Dy()/coords.NormalizedMax,
+	)
+	if rect.Empty() {
+		return nil, fmt.Errorf("normalized region (%d, %d) size %dx%d is empty after clamping", nx, ny, nw, nh)
+	}
+
+	crop := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(crop, crop.Bounds(), img, rect.Min, draw.Src)
+	return crop, nil
+}
+
+// clampNormalized clamps v to the 0-1000 normalized range.
+func clampNormalized(v int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > coords.NormalizedMax {
+		return coords.NormalizedMax
+	}
+	return v
+}
@@ -0,0 +1,67 @@
+package screen
+
+import (
+	"image"
+	"testing"
+)
+
+func TestCropNormalized(t *testing.T) {
+	img := testImage(2000, 1000)
+
+	tests := []struct {
+		name           string
+		nx, ny, nw, nh int
+		want           image.Rectangle // Expected source rectangle
+		wantErr        bool
+	}{
+		{"center", 250, 250, 500, 500, image.Rect(500, 250, 1500, 750), false},
+		{"whole image", 0, 0, 1000, 1000, image.Rect(0, 0, 2000, 1000), false},
+		{"negative origin clamps", -100, -100, 300, 300, image.Rect(0, 0, 400, 200), false},
+		{"overflowing size clamps", 900, 900, 500, 500, image.Rect(1800, 900, 2000, 1000), false},
+		{"outside the image", 1200, 0, 10, 10, image.Rectangle{}, true},
+		{"zero size", 500, 500, 0, 100, image.Rectangle{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crop, err := CropNormalized(img, tt.nx, tt.ny, tt.nw, tt.nh)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got a %v crop", crop.Bounds())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("CropNormalized: %v", err)
+			}
+
+			if got := crop.Bounds(); got != image.Rect(0, 0, tt.want.Dx(), tt.want.Dy()) {
+				t.Errorf("crop bounds = %v, want %dx%d", got, tt.want.Dx(), tt.want.Dy())
+			}
+			corners := [][2]image.Point{
+				{{0, 0}, tt.want.Min},
+				{{tt.want.Dx() - 1, tt.want.Dy() - 1}, tt.want.Max.Sub(image.Pt(1, 1))},
+			}
+			for _, c := range corners {
+				if got, want := crop.RGBAAt(c[0].X, c[0].Y), img.RGBAAt(c[1].X, c[1].Y); got != want {
+					t.Errorf("crop pixel %v = %v, want source pixel %v = %v", c[0], got, c[1], want)
+				}
+			}
+		})
+	}
+}
+
+func TestCropNormalizedOffsetBounds(t *testing.T) {
+	full := testImage(400, 400)
+	sub := full.SubImage(image.Rect(100, 100, 300, 300)).(*image.RGBA)
+
+	crop, err := CropNormalized(sub, 500, 500, 500, 500)
+	if err != nil {
+		t.Fatalf("CropNormalized: %v", err)
+	}
+	if got := crop.Bounds(); got != image.Rect(0, 0, 100, 100) {
+		t.Errorf("crop bounds = %v, want 100x100", got)
+	}
+	if got, want := crop.RGBAAt(0, 0), full.RGBAAt(200, 200); got != want {
+		t.Errorf("crop origin = %v, want %v", got, want)
+	}
+}
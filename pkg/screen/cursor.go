@@ -0,0 +1,45 @@
+package screen
+
+import (
+	"image"
+	"image/color"
+)
+
+// minCursorSize is the smallest cursor glyph height in pixels.
+const minCursorSize = 10
+
+// DrawCursor draws an arrow pointer with its tip at (x, y) onto img, in image
+// coordinates. Screen captures usually omit the hardware cursor, so this lets
+// the model see where the pointer is. The glyph scales with the image height
+// and is clipped to the image bounds.
+func DrawCursor(img *image.RGBA, x, y int) {
+	b := img.Bounds()
+	if !(image.Point{X: x, Y: y}).In(b) {
+		return
+	}
+
+	size := b.Dy() / 45
+	if size < minCursorSize {
+		size = minCursorSize
+	}
+
+	outline := color.RGBA{A: 255}
+	fill := color.RGBA{R: 255, G: 255, B: 255, A: 255}
+
+	// A left-aligned triangle: row r spans columns 0..r*2/3, so the tip is the
+	// top-left corner and the edge slopes down to the right
+	for r := 0; r < size; r++ {
+		width := r * 2 / 3
+		for c := 0; c <= width; c++ {
+			px, py := x+c, y+r
+			if !(image.Point{X: px, Y: py}).In(b) {
+				continue
+			}
+			if c == 0 || c == width || r == size-1 {
+				img.SetRGBA(px, py, outline)
+			} else {
+				img.SetRGBA(px, py, fill)
+			}
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package screen
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func TestDrawCursor(t *testing.T) {
+	grey := color.RGBA{100, 100, 100, 255}
+
+	tests := []struct {
+		name      string
+		x, y      int
+		wantDrawn bool
+	}{
+		{"center", 100, 80, true},
+		{"bottom-right corner is clipped", 199, 159, true},
+		{"outside the image", 250, 80, false},
+		{"negative", -1, 10, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := solidImage(200, 160, grey)
+			base := append([]byte(nil), img.Pix...)
+
+			DrawCursor(img, tt.x, tt.y)
+
+			changed := !bytes.Equal(img.Pix, base)
+			if changed != tt.wantDrawn {
+				t.Fatalf("image changed = %v, want %v", changed, tt.wantDrawn)
+			}
+			if tt.wantDrawn && img.RGBAAt(tt.x, tt.y) == grey {
+				t.Error("pixel at the cursor tip is unchanged")
+			}
+		})
+	}
+}
+
+func TestDrawCursorLeavesRestUntouched(t *testing.T) {
+	grey := color.RGBA{100, 100, 100, 255}
+	img := solidImage(200, 160, grey)
+
+	DrawCursor(img, 50, 50)
+
+	// The glyph extends down and to the right of the tip only
+	for _, p := range []image.Point{{49, 50}, {50, 49}, {0, 0}, {150, 150}} {
+		if got := img.RGBAAt(p.X, p.Y); got != grey {
+			t.Errorf("pixel %v = %v, want it unchanged", p, got)
+		}
+	}
+}
+
+func TestDrawCursorScales(t *testing.T) {
+	glyphHeight := func(w, h int) int {
+		img := solidImage(w, h, color.RGBA{100, 100, 100, 255})
+		DrawCursor(img, 0, 0)
+		rows := 0
+		for y := 0; y < h; y++ {
+			if img.RGBAAt(0, y) != (color.RGBA{100, 100, 100, 255}) {
+				rows++
+			}
+		}
+		return rows
+	}
+
+	if got := glyphHeight(320, 200); got != minCursorSize {
+		t.Errorf("glyph height on a small image = %d, want the minimum %d", got, minCursorSize)
+	}
+	if got, want := glyphHeight(1280, 720), 720/45; got != want {
+		t.Errorf("glyph height on 720p = %d, want %d", got, want)
+	}
+}
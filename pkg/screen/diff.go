@@ -0,0 +1,61 @@
+package screen
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// Diff returns the bounding rectangle of pixels that differ between two
+// same-sized images, relative to the images' top-left corner.
+// A pixel differs when any channel changes by more than tolerance (0-255).
+// The returned rectangle is empty when the images are identical.
+func Diff(before, after image.Image, tolerance uint8) (image.Rectangle, error) {
+	bb, ab := before.Bounds(), after.Bounds()
+	if bb.Dx() != ab.Dx() || bb.Dy() != ab.Dy() {
+		return image.Rectangle{}, fmt.Errorf("image sizes differ: %dx%d vs %dx%d", bb.Dx(), bb.Dy(), ab.Dx(), ab.Dy())
+	}
+
+	minX, minY := bb.Dx(), bb.Dy()
+	maxX, maxY := -1, -1
+	for y := 0; y < bb.Dy(); y++ {
+		for x := 0; x < bb.Dx(); x++ {
+			if !pixelsDiffer(before.At(bb.Min.X+x, bb.Min.Y+y), after.At(ab.Min.X+x, ab.Min.Y+y), tolerance) {
+				continue
+			}
+			if x < minX {
+				minX = x
+			}
+			if y < minY {
+				minY = y
+			}
+			if x > maxX {
+				maxX = x
+			}
+			if y > maxY {
+				maxY = y
+			}
+		}
+	}
+
+	if maxX < 0 {
+		return image.Rectangle{}, nil
+	}
+	return image.Rect(minX, minY, maxX+1, maxY+1), nil
+}
+
+// pixelsDiffer reports whether any 8-bit channel differs by more than tolerance.
+func pixelsDiffer(a, b color.Color, tolerance uint8) bool {
+	ar, ag, ab, aa := a.RGBA()
+	br, bg, bb, ba := b.RGBA()
+	return channelDiff(ar, br) > tolerance || channelDiff(ag, bg) > tolerance ||
+		channelDiff(ab, bb) > tolerance || channelDiff(aa, ba) > tolerance
+}
+
+func channelDiff(a, b uint32) uint8 {
+	a8, b8 := uint8(a>>8), uint8(b>>8)
+	if a8 > b8 {
+		return a8 - b8
+	}
+	return b8 - a8
+}
@@ -0,0 +1,72 @@
+package screen
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+)
+
+// solidImage returns a w x h image filled with c.
+func solidImage(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(c), image.Point{}, draw.Src)
+	return img
+}
+
+func TestDiff(t *testing.T) {
+	grey := color.RGBA{100, 100, 100, 255}
+
+	tests := []struct {
+		name      string
+		change    []image.Point
+		to        color.RGBA
+		tolerance uint8
+		want      image.Rectangle
+	}{
+		{"identical", nil, grey, 0, image.Rectangle{}},
+		{"single pixel", []image.Point{{5, 7}}, color.RGBA{200, 100, 100, 255}, 0, image.Rect(5, 7, 6, 8)},
+		{"bounding box", []image.Point{{2, 3}, {10, 1}, {4, 12}}, color.RGBA{0, 0, 0, 255}, 0, image.Rect(2, 1, 11, 13)},
+		{"within tolerance", []image.Point{{5, 7}}, color.RGBA{110, 95, 100, 255}, 10, image.Rectangle{}},
+		{"above tolerance", []image.Point{{5, 7}}, color.RGBA{111, 100, 100, 255}, 10, image.Rect(5, 7, 6, 8)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := solidImage(16, 16, grey)
+			after := solidImage(16, 16, grey)
+			for _, p := range tt.change {
+				after.Set(p.X, p.Y, tt.to)
+			}
+
+			got, err := Diff(before, after, tt.tolerance)
+			if err != nil {
+				t.Fatalf("Diff: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Diff = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiffRelativeToOrigin(t *testing.T) {
+	grey := color.RGBA{100, 100, 100, 255}
+	before := solidImage(32, 32, grey).SubImage(image.Rect(10, 10, 20, 20))
+	afterFull := solidImage(32, 32, grey)
+	afterFull.Set(12, 13, color.RGBA{255, 0, 0, 255})
+	after := afterFull.SubImage(image.Rect(10, 10, 20, 20))
+
+	got, err := Diff(before, after, 0)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if want := image.Rect(2, 3, 3, 4); got != want {
+		t.Errorf("Diff = %v, want %v", got, want)
+	}
+}
+
+func TestDiffSizeMismatch(t *testing.T) {
+	if _, err := Diff(image.NewRGBA(image.Rect(0, 0, 10, 10)), image.NewRGBA(image.Rect(0, 0, 10, 11)), 0); err == nil {
+		t.Error("expected an error for differently sized images")
+	}
+}
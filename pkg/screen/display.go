@@ -0,0 +1,73 @@
+package screen
+
+import (
+	"github.com/anxuanzi/cua/internal/coords"
+)
+
+// Display describes a connected display.
+type Display struct {
+	Index       int     // Display index (0 = primary)
+	X, Y        int     // Global offset for multi-monitor setups
+	Width       int     // Width in logical pixels
+	Height      int     // Height in logical pixels
+	ScaleFactor float64 // DPI scale factor (1.0 standard, 2.0 Retina)
+	IsPrimary   bool    // Whether this is the primary display
+}
+
+// queryDisplays reads the current display layout from the OS, bypassing the cache.
+// It is a variable so the watcher can be driven by a fake backend.
+var queryDisplays = func() []Display {
+	return toDisplays(coords.QueryAllScreens())
+}
+
+// invalidateDisplays discards the cached display layout.
+// It is a variable so the watcher can be driven by a fake backend.
+var invalidateDisplays = coords.InvalidateScreens
+
+// toDisplays converts internal screen info to the public Display type.
+func toDisplays(screens []coords.ScreenInfo) []Display {
+	displays := make([]Display, len(screens))
+	for i, s := range screens {
+		displays[i] = Display{
+			Index:       s.Index,
+			X:           s.X,
+			Y:           s.Y,
+			Width:       s.Width,
+			Height:      s.Height,
+			ScaleFactor: s.ScaleFactor,
+			IsPrimary:   s.IsPrimary,
+		}
+	}
+	return displays
+}
+
+// Displays returns information about all connected displays.
+// The layout is cached for a few seconds and refreshed sooner when
+// WatchDisplayChanges detects a change or after RefreshDisplays.
+func Displays() []Display {
+	return toDisplays(coords.GetAllScreens())
+}
+
+// NumDisplays returns the number of connected displays.
+func NumDisplays() int {
+	return coords.GetScreenCount()
+}
+
+// RefreshDisplays discards the cached display layout so the next lookup
+// re-reads bounds and scale factors from the OS.
+func RefreshDisplays() {
+	invalidateDisplays()
+}
+
+// sameDisplays reports whether two display layouts are identical.
+func sameDisplays(a, b []Display) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,65 @@
+package screen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"image"
+	"image/jpeg"
+)
+
+// Provider identifies an LLM provider for image encoding.
+// Values match cua.LLMProvider.
+type Provider string
+
+const (
+	ProviderAnthropic Provider = "anthropic"
+	ProviderOpenAI    Provider = "openai"
+	ProviderGemini    Provider = "gemini"
+	ProviderLocal     Provider = "local"
+)
+
+// ModelJPEGQuality is the JPEG quality EncodeForModel uses by default.
+const ModelJPEGQuality = 65
+
+// ImagePart is an image encoded in the shape a provider expects.
+// MIMEType and Data are always set; the other fields only when relevant to
+// the provider:
+//   - Anthropic: Base64 (a base64 image source block)
+//   - Gemini: Data (inline data)
+//   - OpenAI and Local: URL (a data URL)
+type ImagePart struct {
+	Provider Provider
+	MIMEType string
+	Data     []byte // Raw JPEG bytes (Gemini inline data)
+	Base64   string // Base64-encoded JPEG (Anthropic)
+	URL      string // data: URL with base64 JPEG (OpenAI, Local)
+}
+
+// EncodeForModel encodes img as JPEG at quality (1-100; 0 means
+// ModelJPEGQuality) and shapes it for provider. It returns an error for
+// unknown providers.
+func EncodeForModel(img image.Image, provider Provider, quality int) (ImagePart, error) {
+	switch provider {
+	case ProviderAnthropic, ProviderOpenAI, ProviderGemini, ProviderLocal:
+	default:
+		return ImagePart{}, fmt.Errorf("unsupported provider for image encoding: %q", provider)
+	}
+
+	if quality <= 0 {
+		quality = ModelJPEGQuality
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: quality}); err != nil {
+		return ImagePart{}, fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+
+	part := ImagePart{Provider: provider, MIMEType: "image/jpeg", Data: buf.Bytes()}
+	switch provider {
+	case ProviderAnthropic:
+		part.Base64 = base64.StdEncoding.EncodeToString(buf.Bytes())
+	case ProviderOpenAI, ProviderLocal:
+		part.URL = "data:image/jpeg;base64," + base64.StdEncoding.EncodeToString(buf.Bytes())
+	}
+	return part, nil
+}
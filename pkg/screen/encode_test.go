@@ -0,0 +1,85 @@
+package screen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/jpeg"
+	"strings"
+	"testing"
+)
+
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = byte(i * 7)
+	}
+	return img
+}
+
+func TestEncodeForModel(t *testing.T) {
+	img := testImage(64, 48)
+
+	tests := []struct {
+		provider            Provider
+		wantBase64, wantURL bool
+	}{
+		{ProviderAnthropic, true, false},
+		{ProviderGemini, false, false},
+		{ProviderOpenAI, false, true},
+		{ProviderLocal, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.provider), func(t *testing.T) {
+			part, err := EncodeForModel(img, tt.provider, 0)
+			if err != nil {
+				t.Fatalf("EncodeForModel: %v", err)
+			}
+			if part.Provider != tt.provider || part.MIMEType != "image/jpeg" {
+				t.Errorf("got provider %q MIME %q", part.Provider, part.MIMEType)
+			}
+			decoded, err := jpeg.Decode(bytes.NewReader(part.Data))
+			if err != nil {
+				t.Fatalf("Data is not a JPEG: %v", err)
+			}
+			if decoded.Bounds() != img.Bounds() {
+				t.Errorf("decoded bounds %v, want %v", decoded.Bounds(), img.Bounds())
+			}
+
+			if got := part.Base64 != ""; got != tt.wantBase64 {
+				t.Errorf("Base64 set = %v, want %v", got, tt.wantBase64)
+			}
+			if tt.wantBase64 && part.Base64 != base64.StdEncoding.EncodeToString(part.Data) {
+				t.Error("Base64 does not match Data")
+			}
+			if got := part.URL != ""; got != tt.wantURL {
+				t.Errorf("URL set = %v, want %v", got, tt.wantURL)
+			}
+			if tt.wantURL && !strings.HasPrefix(part.URL, "data:image/jpeg;base64,") {
+				t.Errorf("URL = %.40q, want a JPEG data URL", part.URL)
+			}
+		})
+	}
+}
+
+func TestEncodeForModelQuality(t *testing.T) {
+	img := testImage(128, 128)
+
+	low, err := EncodeForModel(img, ProviderGemini, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	high, err := EncodeForModel(img, ProviderGemini, 95)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(low.Data) >= len(high.Data) {
+		t.Errorf("quality 10 gave %d bytes, quality 95 gave %d; want lower quality to be smaller", len(low.Data), len(high.Data))
+	}
+}
+
+func TestEncodeForModelUnknownProvider(t *testing.T) {
+	if _, err := EncodeForModel(testImage(8, 8), Provider("acme"), 0); err == nil {
+		t.Error("expected an error for an unknown provider")
+	}
+}
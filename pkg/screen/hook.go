@@ -0,0 +1,20 @@
+package screen
+
+import (
+	"image"
+	"time"
+)
+
+// CaptureMeta describes a screenshot passed to a CaptureHook.
+type CaptureMeta struct {
+	ScreenIndex    int       // Screen index captured from
+	OriginalWidth  int       // Captured width in physical pixels
+	OriginalHeight int       // Captured height in physical pixels
+	ScaledWidth    int       // Width after resizing for the model
+	ScaledHeight   int       // Height after resizing for the model
+	CapturedAt     time.Time // When the capture was taken
+}
+
+// CaptureHook post-processes a screenshot after resizing and before encoding.
+// It may modify img in place or return a replacement; returning nil keeps img.
+type CaptureHook func(img *image.RGBA, meta CaptureMeta) *image.RGBA
@@ -0,0 +1,66 @@
+package screen
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+
+	"github.com/go-vgo/robotgo"
+	"golang.org/x/image/draw"
+)
+
+// Dimensions describes a capture before and after downscaling.
+type Dimensions struct {
+	OriginalWidth  int // Captured width in pixels
+	OriginalHeight int // Captured height in pixels
+	Width          int // Encoded width in pixels
+	Height         int // Encoded height in pixels
+}
+
+// CaptureJPEGBytes is a fast path that captures a display and returns it as
+// JPEG bytes, downscaled so its longest side is at most maxDim (no limit if
+// maxDim <= 0). If displayIndex is -1, captures the primary display.
+//
+// The capture is encoded straight from robotgo's RGBA buffer, downscaled in a
+// single bilinear pass when needed, with no intermediate copies. Use Capture
+// and Resize instead when you need the image itself or CatmullRom quality.
+func CaptureJPEGBytes(displayIndex, quality, maxDim int) ([]byte, Dimensions, error) {
+	if displayIndex >= 0 {
+		robotgo.DisplayID = displayIndex
+		defer func() { robotgo.DisplayID = -1 }()
+	}
+
+	img, err := CaptureWithRetry(func() (image.Image, error) { return robotgo.CaptureImg() })
+	if err != nil {
+		return nil, Dimensions{}, fmt.Errorf("failed to capture screen: %w", err)
+	}
+	return encodeJPEGScaled(img, quality, maxDim)
+}
+
+// encodeJPEGScaled downscales img to fit maxDim and encodes it as JPEG.
+func encodeJPEGScaled(img image.Image, quality, maxDim int) ([]byte, Dimensions, error) {
+	b := img.Bounds()
+	dims := Dimensions{
+		OriginalWidth:  b.Dx(),
+		OriginalHeight: b.Dy(),
+		Width:          b.Dx(),
+		Height:         b.Dy(),
+	}
+	if maxDim > 0 {
+		dims.Width, dims.Height = CalculateScaledDimensions(b.Dx(), b.Dy(), maxDim, maxDim)
+	}
+
+	src := img
+	if dims.Width != b.Dx() || dims.Height != b.Dy() {
+		scaled := image.NewRGBA(image.Rect(0, 0, dims.Width, dims.Height))
+		draw.ApproxBiLinear.Scale(scaled, scaled.Bounds(), img, b, draw.Src, nil)
+		src = scaled
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, src, &jpeg.Options{Quality: quality}); err != nil {
+		return nil, Dimensions{}, fmt.Errorf("failed to encode JPEG: %w", err)
+	}
+	return buf.Bytes(), dims, nil
+}
@@ -0,0 +1,69 @@
+package screen
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+func TestEncodeJPEGScaled(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h, maxDim int
+		wantW, wantH int
+	}{
+		{"no limit", 640, 480, 0, 640, 480},
+		{"already fits", 640, 480, 1280, 640, 480},
+		{"landscape", 2560, 1440, 1280, 1280, 720},
+		{"portrait", 1440, 2560, 1280, 720, 1280},
+		{"square", 2000, 2000, 500, 500, 500},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, dims, err := encodeJPEGScaled(testImage(tt.w, tt.h), 80, tt.maxDim)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := Dimensions{OriginalWidth: tt.w, OriginalHeight: tt.h, Width: tt.wantW, Height: tt.wantH}
+			if dims != want {
+				t.Errorf("dims = %+v, want %+v", dims, want)
+			}
+
+			decoded, err := jpeg.Decode(bytes.NewReader(data))
+			if err != nil {
+				t.Fatalf("output is not a JPEG: %v", err)
+			}
+			if got := decoded.Bounds().Size(); got != image.Pt(tt.wantW, tt.wantH) {
+				t.Errorf("decoded size %v, want %dx%d", got, tt.wantW, tt.wantH)
+			}
+		})
+	}
+}
+
+// benchmarkCapture is a synthetic 4K frame standing in for a robotgo capture.
+var benchmarkCapture = testImage(3840, 2160)
+
+// BenchmarkEncodeJPEGScaled measures the CaptureJPEGBytes fast path after the
+// capture itself: one bilinear downscale and a JPEG encode.
+func BenchmarkEncodeJPEGScaled(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := encodeJPEGScaled(benchmarkCapture, 65, 1280); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// BenchmarkResizeThenEncodeJPEG is the slow path the fast path replaces:
+// a CatmullRom Resize followed by a JPEG encode.
+func BenchmarkResizeThenEncodeJPEG(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		resized, _, _ := Resize(benchmarkCapture, 1280, 1280)
+		var buf bytes.Buffer
+		if err := jpeg.Encode(&buf, resized, &jpeg.Options{Quality: 65}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package screen
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"sync/atomic"
+)
+
+// pngCompression holds the png.CompressionLevel used by CaptureAndProcess and
+// EncodeToBase64PNG. Its zero value is png.DefaultCompression.
+var pngCompression atomic.Int32
+
+// SetPNGCompression sets the compression level used by CaptureAndProcess and
+// EncodeToBase64PNG. Use png.BestSpeed for fast debugging dumps or
+// png.BestCompression for smaller archives. Unknown levels select
+// png.DefaultCompression. It is safe to call while captures are running.
+func SetPNGCompression(level png.CompressionLevel) {
+	switch level {
+	case png.DefaultCompression, png.NoCompression, png.BestSpeed, png.BestCompression:
+	default:
+		level = png.DefaultCompression
+	}
+	pngCompression.Store(int32(level))
+}
+
+// PNGCompression returns the compression level used by CaptureAndProcess and
+// EncodeToBase64PNG.
+func PNGCompression() png.CompressionLevel {
+	return png.CompressionLevel(pngCompression.Load())
+}
+
+// EncodePNG encodes img as PNG at the given compression level.
+func EncodePNG(img image.Image, level png.CompressionLevel) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := png.Encoder{CompressionLevel: level}
+	if err := enc.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode PNG: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// SavePNG writes img to path as PNG at the given compression level.
+func SavePNG(path string, img image.Image, level png.CompressionLevel) error {
+	data, err := EncodePNG(img, level)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write PNG: %w", err)
+	}
+	return nil
+}
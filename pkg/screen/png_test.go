@@ -0,0 +1,72 @@
+package screen
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+)
+
+// stripes returns a compressible synthetic image.
+func stripes(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x / 8 * 16), G: uint8(y % 7 * 30), B: 200, A: 255})
+		}
+	}
+	return img
+}
+
+func TestEncodePNGLevels(t *testing.T) {
+	img := stripes(256, 256)
+
+	sizes := map[png.CompressionLevel]int{}
+	for _, level := range []png.CompressionLevel{png.BestSpeed, png.BestCompression} {
+		data, err := EncodePNG(img, level)
+		if err != nil {
+			t.Fatalf("level %d: %v", level, err)
+		}
+		decoded, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("level %d: output does not decode: %v", level, err)
+		}
+		if decoded.Bounds() != img.Bounds() {
+			t.Errorf("level %d: decoded bounds %v, want %v", level, decoded.Bounds(), img.Bounds())
+		}
+		if got, want := color.RGBAModel.Convert(decoded.At(100, 50)), img.At(100, 50); got != want {
+			t.Errorf("level %d: pixel changed after round trip", level)
+		}
+		sizes[level] = len(data)
+	}
+
+	if sizes[png.BestCompression] >= sizes[png.BestSpeed] {
+		t.Errorf("BestCompression gave %d bytes, BestSpeed %d; want BestCompression smaller",
+			sizes[png.BestCompression], sizes[png.BestSpeed])
+	}
+}
+
+func TestSetPNGCompression(t *testing.T) {
+	t.Cleanup(func() { SetPNGCompression(png.DefaultCompression) })
+
+	if got := PNGCompression(); got != png.DefaultCompression {
+		t.Errorf("default PNGCompression() = %d, want DefaultCompression", got)
+	}
+
+	tests := []struct {
+		level, want png.CompressionLevel
+	}{
+		{png.BestSpeed, png.BestSpeed},
+		{png.BestCompression, png.BestCompression},
+		{png.NoCompression, png.NoCompression},
+		{png.DefaultCompression, png.DefaultCompression},
+		{png.CompressionLevel(42), png.DefaultCompression},
+	}
+	for _, tt := range tests {
+		SetPNGCompression(tt.level)
+		if got := PNGCompression(); got != tt.want {
+			t.Errorf("SetPNGCompression(%d): PNGCompression() = %d, want %d", tt.level, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package screen
+
+import (
+	"image"
+	"math"
+)
+
+// Grayscale converts img to grayscale in place, setting R, G, and B of each
+// pixel to its luma (ITU-R BT.601). Screenshots of text-heavy UIs lose little
+// for the model without color and encode smaller, but color-only cues (e.g.,
+// red error borders, status dots) are lost.
+func Grayscale(img *image.RGBA) {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			r, g, bl := uint32(row[i]), uint32(row[i+1]), uint32(row[i+2])
+			luma := uint8((299*r + 587*g + 114*bl + 500) / 1000)
+			row[i], row[i+1], row[i+2] = luma, luma, luma
+		}
+	}
+}
+
+// Quantize reduces img in place to a uniform palette of at most n colors
+// (n >= 2; smaller values are raised to 2). Grayscale images get n gray
+// levels; color images get floor(cbrt(n)) levels per channel, at least 2, so
+// they keep up to 8 colors even when n is smaller.
+// Fewer colors remove gradients and noise so frames compress better, at the
+// cost of banding and of faint UI details (e.g., subtle borders, disabled
+// text) that may become indistinguishable from their background.
+func Quantize(img *image.RGBA, n int) {
+	if n < 2 {
+		n = 2
+	}
+
+	levels := n
+	if !isGray(img) {
+		levels = int(math.Cbrt(float64(n)) + 1e-9)
+		if levels < 2 {
+			levels = 2
+		}
+	}
+
+	var table [256]uint8
+	steps := levels - 1
+	for v := range table {
+		level := (v*steps + 127) / 255
+		table[v] = uint8(level * 255 / steps)
+	}
+
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			row[i], row[i+1], row[i+2] = table[row[i]], table[row[i+1]], table[row[i+2]]
+		}
+	}
+}
+
+// isGray reports whether every pixel of img has R == G == B.
+func isGray(img *image.RGBA) bool {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		row := img.Pix[img.PixOffset(b.Min.X, y):img.PixOffset(b.Max.X, y)]
+		for i := 0; i < len(row); i += 4 {
+			if row[i] != row[i+1] || row[i] != row[i+2] {
+				return false
+			}
+		}
+	}
+	return true
+}
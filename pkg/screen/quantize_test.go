@@ -0,0 +1,115 @@
+package screen
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"testing"
+)
+
+// gradient returns a w x h image with a horizontal red ramp, a vertical green
+// ramp, and a diagonal blue ramp.
+func gradient(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.SetRGBA(x, y, color.RGBA{uint8(x * 255 / (w - 1)), uint8(y * 255 / (h - 1)), uint8((x + y) * 255 / (w + h - 2)), 255})
+		}
+	}
+	return img
+}
+
+// uniqueColors counts the distinct RGB colors in img.
+func uniqueColors(img *image.RGBA) int {
+	seen := make(map[[3]uint8]bool)
+	for i := 0; i < len(img.Pix); i += 4 {
+		seen[[3]uint8{img.Pix[i], img.Pix[i+1], img.Pix[i+2]}] = true
+	}
+	return len(seen)
+}
+
+func TestGrayscale(t *testing.T) {
+	img := gradient(64, 48)
+	Grayscale(img)
+
+	for i := 0; i < len(img.Pix); i += 4 {
+		if r, g, b := img.Pix[i], img.Pix[i+1], img.Pix[i+2]; r != g || g != b {
+			t.Fatalf("pixel %d = (%d, %d, %d), want R=G=B", i/4, r, g, b)
+		}
+		if img.Pix[i+3] != 255 {
+			t.Fatalf("pixel %d alpha changed to %d", i/4, img.Pix[i+3])
+		}
+	}
+
+	// BT.601 luma of pure colors
+	for _, tt := range []struct {
+		in   color.RGBA
+		want uint8
+	}{
+		{color.RGBA{255, 0, 0, 255}, 76},
+		{color.RGBA{0, 255, 0, 255}, 150},
+		{color.RGBA{0, 0, 255, 255}, 29},
+		{color.RGBA{255, 255, 255, 255}, 255},
+	} {
+		px := image.NewRGBA(image.Rect(0, 0, 1, 1))
+		px.SetRGBA(0, 0, tt.in)
+		Grayscale(px)
+		if got := px.Pix[0]; got != tt.want {
+			t.Errorf("luma of %v = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestQuantize(t *testing.T) {
+	tests := []struct {
+		n         int
+		grayscale bool
+		max       int
+	}{
+		{2, true, 2},
+		{4, true, 4},
+		{16, true, 16},
+		{8, false, 8},
+		{27, false, 27},
+		{100, false, 100},
+		{2, false, 8}, // Color keeps at least two levels per channel
+		{0, true, 2},  // Raised to 2
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("n=%d gray=%v", tt.n, tt.grayscale), func(t *testing.T) {
+			img := gradient(256, 256)
+			if tt.grayscale {
+				Grayscale(img)
+			}
+			before := uniqueColors(img)
+
+			Quantize(img, tt.n)
+
+			got := uniqueColors(img)
+			if got > tt.max {
+				t.Errorf("%d colors after quantizing, want at most %d", got, tt.max)
+			}
+			if got >= before {
+				t.Errorf("quantizing did not reduce colors: %d -> %d", before, got)
+			}
+			if tt.grayscale && !isGray(img) {
+				t.Error("quantizing a grayscale image introduced color")
+			}
+		})
+	}
+}
+
+func TestQuantizeKeepsExtremes(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 2, 1))
+	img.SetRGBA(0, 0, color.RGBA{0, 0, 0, 255})
+	img.SetRGBA(1, 0, color.RGBA{255, 255, 255, 255})
+
+	Quantize(img, 8)
+
+	if got := img.RGBAAt(0, 0); got != (color.RGBA{0, 0, 0, 255}) {
+		t.Errorf("black became %v", got)
+	}
+	if got := img.RGBAAt(1, 0); got != (color.RGBA{255, 255, 255, 255}) {
+		t.Errorf("white became %v", got)
+	}
+}
@@ -0,0 +1,61 @@
+package screen
+
+import (
+	"errors"
+	"image"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultCaptureRetries is the default number of times a failed capture is retried.
+const DefaultCaptureRetries = 2
+
+// captureRetryBackoff is the delay before the first retry; it grows linearly.
+const captureRetryBackoff = 100 * time.Millisecond
+
+// errEmptyCapture is returned when the backend reports success but no image.
+var errEmptyCapture = errors.New("capture returned no image")
+
+var captureRetries atomic.Int32
+
+func init() {
+	captureRetries.Store(DefaultCaptureRetries)
+}
+
+// retrySleep waits between capture attempts.
+// It is a variable so retries can be driven without real delays.
+var retrySleep = time.Sleep
+
+// SetCaptureRetries sets how many times a failed capture is retried, with a
+// short growing backoff, before the error is returned. Captures can fail
+// transiently while displays are reconfigured or permissions are re-checked.
+// 0 disables retries. It applies to all captures in this package and to the
+// agent's screenshots.
+func SetCaptureRetries(n int) {
+	if n < 0 {
+		n = 0
+	}
+	captureRetries.Store(int32(n))
+}
+
+// CaptureRetries returns the number of times a failed capture is retried.
+func CaptureRetries() int {
+	return int(captureRetries.Load())
+}
+
+// CaptureWithRetry calls capture, retrying failures (including a nil image)
+// up to CaptureRetries times. Validate arguments before calling it: errors
+// from invalid input are permanent and would only be retried pointlessly.
+func CaptureWithRetry(capture func() (image.Image, error)) (image.Image, error) {
+	retries := CaptureRetries()
+	for attempt := 0; ; attempt++ {
+		img, err := capture()
+		if err == nil && img == nil {
+			err = errEmptyCapture
+		}
+		if err == nil || attempt >= retries {
+			return img, err
+		}
+		retrySleep(time.Duration(attempt+1) * captureRetryBackoff)
+	}
+}
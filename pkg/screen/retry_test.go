@@ -0,0 +1,142 @@
+package screen
+
+import (
+	"errors"
+	"image"
+	"testing"
+	"time"
+)
+
+// fakeSleep replaces retrySleep with one that records delays instead of waiting.
+func fakeSleep(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var delays []time.Duration
+	orig := retrySleep
+	t.Cleanup(func() { retrySleep = orig })
+	retrySleep = func(d time.Duration) { delays = append(delays, d) }
+	return &delays
+}
+
+func TestCaptureWithRetry(t *testing.T) {
+	errBusy := errors.New("display busy")
+	frame := image.NewRGBA(image.Rect(0, 0, 4, 4))
+
+	type attempt struct {
+		img image.Image
+		err error
+	}
+	tests := []struct {
+		name       string
+		retries    int
+		attempts   []attempt
+		wantCalls  int
+		wantErr    error
+		wantDelays []time.Duration
+	}{
+		{
+			name:      "first try",
+			retries:   2,
+			attempts:  []attempt{{frame, nil}},
+			wantCalls: 1,
+		},
+		{
+			name:       "recovers after failures",
+			retries:    2,
+			attempts:   []attempt{{nil, errBusy}, {nil, errBusy}, {frame, nil}},
+			wantCalls:  3,
+			wantDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:       "gives up after retries",
+			retries:    2,
+			attempts:   []attempt{{nil, errBusy}, {nil, errBusy}, {nil, errBusy}, {frame, nil}},
+			wantCalls:  3,
+			wantErr:    errBusy,
+			wantDelays: []time.Duration{100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			name:       "nil image is retried",
+			retries:    1,
+			attempts:   []attempt{{nil, nil}, {frame, nil}},
+			wantCalls:  2,
+			wantDelays: []time.Duration{100 * time.Millisecond},
+		},
+		{
+			name:      "nil image without retries",
+			retries:   0,
+			attempts:  []attempt{{nil, nil}},
+			wantCalls: 1,
+			wantErr:   errEmptyCapture,
+		},
+		{
+			name:      "retries disabled",
+			retries:   0,
+			attempts:  []attempt{{nil, errBusy}, {frame, nil}},
+			wantCalls: 1,
+			wantErr:   errBusy,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delays := fakeSleep(t)
+			t.Cleanup(func() { SetCaptureRetries(DefaultCaptureRetries) })
+			SetCaptureRetries(tt.retries)
+
+			calls := 0
+			img, err := CaptureWithRetry(func() (image.Image, error) {
+				a := tt.attempts[calls]
+				calls++
+				return a.img, a.err
+			})
+
+			if calls != tt.wantCalls {
+				t.Errorf("capture called %d times, want %d", calls, tt.wantCalls)
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("err = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr == nil && img != image.Image(frame) {
+				t.Error("did not return the captured frame")
+			}
+			if len(*delays) != len(tt.wantDelays) {
+				t.Fatalf("slept %v, want %v", *delays, tt.wantDelays)
+			}
+			for i, d := range tt.wantDelays {
+				if (*delays)[i] != d {
+					t.Errorf("delay %d = %v, want %v", i, (*delays)[i], d)
+				}
+			}
+		})
+	}
+}
+
+func TestSetCaptureRetries(t *testing.T) {
+	t.Cleanup(func() { SetCaptureRetries(DefaultCaptureRetries) })
+
+	if got := CaptureRetries(); got != DefaultCaptureRetries {
+		t.Errorf("default CaptureRetries() = %d, want %d", got, DefaultCaptureRetries)
+	}
+	for _, tt := range []struct{ set, want int }{{5, 5}, {0, 0}, {-3, 0}} {
+		SetCaptureRetries(tt.set)
+		if got := CaptureRetries(); got != tt.want {
+			t.Errorf("SetCaptureRetries(%d): CaptureRetries() = %d, want %d", tt.set, got, tt.want)
+		}
+	}
+}
+
+func TestCaptureInvalidDisplayNotRetried(t *testing.T) {
+	delays := fakeSleep(t)
+	orig := numDisplays
+	t.Cleanup(func() { numDisplays = orig })
+	numDisplays = func() int { return 2 }
+
+	for _, index := range []int{2, 5} {
+		_, err := Capture(index)
+		if !errors.Is(err, ErrInvalidDisplay) {
+			t.Errorf("Capture(%d) error = %v, want ErrInvalidDisplay", index, err)
+		}
+	}
+	if len(*delays) != 0 {
+		t.Errorf("invalid display retried with delays %v", *delays)
+	}
+}
@@ -0,0 +1,48 @@
+package screen
+
+import (
+	"fmt"
+	"image"
+	"math"
+)
+
+// ScaleTolerance is the relative difference between the horizontal and
+// vertical capture scale that CheckCaptureScale accepts.
+const ScaleTolerance = 0.02
+
+// ScaleMismatchError reports a capture whose size is inconsistent with the
+// display's logical size, so converting between the two would skew coordinates.
+type ScaleMismatchError struct {
+	LogicalWidth, LogicalHeight   int // Display size in logical pixels
+	CapturedWidth, CapturedHeight int // Captured image size in pixels
+}
+
+func (e *ScaleMismatchError) Error() string {
+	return fmt.Sprintf("captured image %dx%d does not match display %dx%d at a uniform scale (x%.3f horizontally, x%.3f vertically)",
+		e.CapturedWidth, e.CapturedHeight, e.LogicalWidth, e.LogicalHeight,
+		float64(e.CapturedWidth)/float64(e.LogicalWidth), float64(e.CapturedHeight)/float64(e.LogicalHeight))
+}
+
+// CheckCaptureScale verifies that a capture of captured size is the display's
+// logical size scaled by one factor of at least 1 (e.g., 2x on Retina), within
+// ScaleTolerance. Coordinate conversion assumes this, so a mismatch - a
+// stale display layout or a capture of the wrong display - skews every action.
+// It returns a *ScaleMismatchError describing the mismatch, or nil.
+func CheckCaptureScale(logicalWidth, logicalHeight int, captured image.Rectangle) error {
+	if logicalWidth <= 0 || logicalHeight <= 0 {
+		return nil
+	}
+
+	scaleX := float64(captured.Dx()) / float64(logicalWidth)
+	scaleY := float64(captured.Dy()) / float64(logicalHeight)
+	if math.Abs(scaleX-scaleY) <= ScaleTolerance*math.Max(scaleX, scaleY) && math.Min(scaleX, scaleY) >= 1-ScaleTolerance {
+		return nil
+	}
+
+	return &ScaleMismatchError{
+		LogicalWidth:   logicalWidth,
+		LogicalHeight:  logicalHeight,
+		CapturedWidth:  captured.Dx(),
+		CapturedHeight: captured.Dy(),
+	}
+}
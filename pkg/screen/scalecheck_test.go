@@ -0,0 +1,50 @@
+package screen
+
+import (
+	"errors"
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestCheckCaptureScale(t *testing.T) {
+	tests := []struct {
+		name          string
+		logicalW      int
+		logicalH      int
+		captured      image.Rectangle
+		wantMismatch  bool
+		wantErrString string
+	}{
+		{"standard display", 1920, 1080, image.Rect(0, 0, 1920, 1080), false, ""},
+		{"retina display", 1440, 900, image.Rect(0, 0, 2880, 1800), false, ""},
+		{"fractional scale", 1280, 720, image.Rect(0, 0, 1600, 900), false, ""},
+		{"within tolerance", 1920, 1080, image.Rect(0, 0, 1920, 1090), false, ""},
+		{"unknown logical size", 0, 0, image.Rect(0, 0, 10, 10), false, ""},
+		{"stale layout after rotation", 1920, 1080, image.Rect(0, 0, 1080, 1920), true, "captured image 1080x1920 does not match display 1920x1080"},
+		{"wrong display", 2560, 1440, image.Rect(0, 0, 1920, 1200), true, "x0.750 horizontally, x0.833 vertically"},
+		{"capture smaller than logical size", 2880, 1800, image.Rect(0, 0, 1440, 900), true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckCaptureScale(tt.logicalW, tt.logicalH, tt.captured)
+			if !tt.wantMismatch {
+				if err != nil {
+					t.Errorf("unexpected mismatch: %v", err)
+				}
+				return
+			}
+
+			var mismatch *ScaleMismatchError
+			if !errors.As(err, &mismatch) {
+				t.Fatalf("err = %v, want a *ScaleMismatchError", err)
+			}
+			if mismatch.CapturedWidth != tt.captured.Dx() || mismatch.LogicalWidth != tt.logicalW {
+				t.Errorf("mismatch = %+v", mismatch)
+			}
+			if tt.wantErrString != "" && !strings.Contains(err.Error(), tt.wantErrString) {
+				t.Errorf("error %q does not mention %q", err, tt.wantErrString)
+			}
+		})
+	}
+}
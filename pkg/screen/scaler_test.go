@@ -0,0 +1,71 @@
+package screen
+
+import (
+	"image"
+	"testing"
+
+	"golang.org/x/image/draw"
+)
+
+var allScalers = []Scaler{ScalerNearestNeighbor, ScalerBiLinear, ScalerCatmullRom}
+
+func TestScalerInterpolator(t *testing.T) {
+	tests := []struct {
+		scaler Scaler
+		want   draw.Interpolator
+	}{
+		{ScalerNearestNeighbor, draw.NearestNeighbor},
+		{ScalerBiLinear, draw.ApproxBiLinear},
+		{ScalerCatmullRom, draw.CatmullRom},
+		{"", draw.ApproxBiLinear},
+		{"lanczos", draw.ApproxBiLinear},
+	}
+	for _, tt := range tests {
+		if got := tt.scaler.Interpolator(); got != tt.want {
+			t.Errorf("Scaler(%q).Interpolator() = %T, want %T", tt.scaler, got, tt.want)
+		}
+	}
+}
+
+func TestResizeWithDimensions(t *testing.T) {
+	tests := []struct {
+		name         string
+		w, h         int
+		maxW, maxH   int
+		wantW, wantH int
+	}{
+		{"fits", 800, 600, 1280, 800, 800, 600},
+		{"4K landscape", 3840, 2160, 1280, 800, 1280, 720},
+		{"5K 16:10", 5120, 3200, 1280, 800, 1280, 800},
+		{"tall", 1080, 1920, 1280, 800, 450, 800},
+	}
+	for _, scaler := range allScalers {
+		for _, tt := range tests {
+			t.Run(string(scaler)+"/"+tt.name, func(t *testing.T) {
+				src := testImage(tt.w, tt.h)
+				img, w, h := ResizeWith(src, tt.maxW, tt.maxH, scaler)
+				if w != tt.wantW || h != tt.wantH {
+					t.Errorf("reported %dx%d, want %dx%d", w, h, tt.wantW, tt.wantH)
+				}
+				if got := img.Bounds().Size(); got != image.Pt(tt.wantW, tt.wantH) {
+					t.Errorf("image is %v, want %dx%d", got, tt.wantW, tt.wantH)
+				}
+				if tt.w == tt.wantW && img != image.Image(src) {
+					t.Error("image that already fits was copied")
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkResizeWith(b *testing.B) {
+	src := testImage(5120, 2880) // 5K capture
+	for _, scaler := range allScalers {
+		b.Run(string(scaler), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ResizeWith(src, DefaultMaxWidth, DefaultMaxHeight, scaler)
+			}
+		})
+	}
+}
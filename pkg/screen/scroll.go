@@ -0,0 +1,118 @@
+package screen
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/go-vgo/robotgo"
+	"golang.org/x/image/draw"
+)
+
+// MaxScrollFrames caps the number of frames CaptureScrolling stitches together.
+const MaxScrollFrames = 50
+
+// captureRegionImage captures a screen region.
+// It is a variable so scrolling capture can be driven by a fake backend.
+var captureRegionImage = func(region image.Rectangle) (image.Image, error) {
+	return robotgo.CaptureImg(region.Min.X, region.Min.Y, region.Dx(), region.Dy())
+}
+
+// CaptureScrolling captures a scrollable region into one tall image, e.g. a
+// long page or list. It captures the region, calls scrollFn to scroll it, and
+// repeats until scrollFn returns false (nothing more to scroll), the content
+// stops moving, or MaxScrollFrames is reached.
+//
+// Consecutive frames are stitched vertically. overlap is the maximum number of
+// rows the end of one frame may repeat at the start of the next; the largest
+// matching overlap is detected and de-duplicated so content is not repeated.
+func CaptureScrolling(region image.Rectangle, scrollFn func() bool, overlap int) (*image.RGBA, error) {
+	if region.Empty() {
+		return nil, fmt.Errorf("capture region is empty: %v", region)
+	}
+	if scrollFn == nil {
+		return nil, fmt.Errorf("scrollFn is required")
+	}
+
+	first, err := captureScrollFrame(region)
+	if err != nil {
+		return nil, err
+	}
+
+	frames := []*image.RGBA{first}
+	newRows := []int{first.Bounds().Dy()}
+	height := first.Bounds().Dy()
+
+	for len(frames) < MaxScrollFrames && scrollFn() {
+		frame, err := captureScrollFrame(region)
+		if err != nil {
+			return nil, err
+		}
+
+		prev := frames[len(frames)-1]
+		if bytes.Equal(prev.Pix, frame.Pix) {
+			// The content didn't move; we've reached the end
+			break
+		}
+
+		skip := matchOverlap(prev, frame, overlap)
+		frames = append(frames, frame)
+		newRows = append(newRows, frame.Bounds().Dy()-skip)
+		height += frame.Bounds().Dy() - skip
+	}
+
+	width := first.Bounds().Dx()
+	stitched := image.NewRGBA(image.Rect(0, 0, width, height))
+	y := 0
+	for i, frame := range frames {
+		fh := frame.Bounds().Dy()
+		src := image.Rect(0, fh-newRows[i], width, fh)
+		draw.Draw(stitched, image.Rect(0, y, width, y+newRows[i]), frame, src.Min, draw.Src)
+		y += newRows[i]
+	}
+
+	return stitched, nil
+}
+
+// captureScrollFrame captures the region as an RGBA image anchored at (0, 0).
+func captureScrollFrame(region image.Rectangle) (*image.RGBA, error) {
+	img, err := CaptureWithRetry(func() (image.Image, error) { return captureRegionImage(region) })
+	if err != nil {
+		return nil, fmt.Errorf("failed to capture region %v: %w", region, err)
+	}
+
+	b := img.Bounds()
+	if b.Dx() != region.Dx() || b.Dy() != region.Dy() {
+		return nil, fmt.Errorf("captured frame is %dx%d, expected %dx%d", b.Dx(), b.Dy(), region.Dx(), region.Dy())
+	}
+
+	frame := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(frame, frame.Bounds(), img, b.Min, draw.Src)
+	return frame, nil
+}
+
+// matchOverlap returns the largest k <= maxOverlap such that the last k rows
+// of prev equal the first k rows of next, or 0 if none match.
+func matchOverlap(prev, next *image.RGBA, maxOverlap int) int {
+	h := prev.Bounds().Dy()
+	if maxOverlap > h {
+		maxOverlap = h
+	}
+	rowBytes := prev.Bounds().Dx() * 4
+
+	for k := maxOverlap; k > 0; k-- {
+		match := true
+		for row := 0; row < k; row++ {
+			p := prev.Pix[(h-k+row)*prev.Stride:][:rowBytes]
+			n := next.Pix[row*next.Stride:][:rowBytes]
+			if !bytes.Equal(p, n) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return k
+		}
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package screen
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"strings"
+	"testing"
+)
+
+// testPage returns a tall page whose rows are all distinct.
+func testPage(w, h int) *image.RGBA {
+	page := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			page.Set(x, y, color.RGBA{R: uint8(y), G: uint8(y >> 8), B: uint8(x), A: 255})
+		}
+	}
+	return page
+}
+
+// fakeScroller shows a window of page through captureRegionImage and scrolls
+// it by step rows per scroll, stopping at the bottom of the page.
+type fakeScroller struct {
+	page   *image.RGBA
+	offset int
+	step   int
+}
+
+func (f *fakeScroller) install(t *testing.T) {
+	t.Helper()
+	orig := captureRegionImage
+	t.Cleanup(func() { captureRegionImage = orig })
+	captureRegionImage = func(region image.Rectangle) (image.Image, error) {
+		// Offset the window so the frame is not anchored at (0, 0)
+		window := image.Rect(0, f.offset, region.Dx(), f.offset+region.Dy())
+		return f.page.SubImage(window), nil
+	}
+}
+
+func (f *fakeScroller) scroll() bool {
+	f.offset += f.step
+	if max := f.page.Bounds().Dy() - 20; f.offset > max {
+		f.offset = max // Like a real page, scrolling stops at the bottom
+	}
+	return true
+}
+
+func TestCaptureScrolling(t *testing.T) {
+	region := image.Rect(100, 200, 108, 220) // 8x20 window
+
+	tests := []struct {
+		name       string
+		pageHeight int
+		step       int
+		overlap    int
+		wantHeight int // Stitched height; the page height when stitched exactly
+	}{
+		{"overlapping frames", 95, 15, 10, 95},
+		{"overlap larger than needed", 100, 15, 20, 100},
+		{"adjacent frames", 100, 20, 10, 100},
+		{"single screen", 20, 10, 10, 20},
+		{"frame cap", 10000, 20, 0, MaxScrollFrames * 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := testPage(8, tt.pageHeight)
+			f := &fakeScroller{page: page, step: tt.step}
+			f.install(t)
+
+			got, err := CaptureScrolling(region, f.scroll, tt.overlap)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Bounds() != image.Rect(0, 0, 8, tt.wantHeight) {
+				t.Fatalf("stitched bounds %v, want 8x%d", got.Bounds(), tt.wantHeight)
+			}
+			for y := 0; y < tt.wantHeight; y++ {
+				if got.RGBAAt(3, y) != page.RGBAAt(3, y) {
+					t.Fatalf("row %d = %v, want page row %v", y, got.RGBAAt(3, y), page.RGBAAt(3, y))
+				}
+			}
+		})
+	}
+}
+
+func TestCaptureScrollingStopsWhenScrollFnDeclines(t *testing.T) {
+	f := &fakeScroller{page: testPage(8, 100), step: 10}
+	f.install(t)
+
+	got, err := CaptureScrolling(image.Rect(0, 0, 8, 20), func() bool { return false }, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Bounds().Dy() != 20 {
+		t.Errorf("stitched height %d, want a single 20-row frame", got.Bounds().Dy())
+	}
+}
+
+func TestCaptureScrollingErrors(t *testing.T) {
+	fakeSleep(t)
+	t.Cleanup(func() { SetCaptureRetries(DefaultCaptureRetries) })
+	SetCaptureRetries(0)
+
+	errBackend := errors.New("backend failed")
+	orig := captureRegionImage
+	t.Cleanup(func() { captureRegionImage = orig })
+
+	tests := []struct {
+		name     string
+		region   image.Rectangle
+		scrollFn func() bool
+		capture  func(image.Rectangle) (image.Image, error)
+		wantErr  string
+	}{
+		{"empty region", image.Rect(0, 0, 0, 10), func() bool { return false }, nil, "capture region is empty"},
+		{"no scrollFn", image.Rect(0, 0, 8, 8), nil, nil, "scrollFn is required"},
+		{
+			"capture fails", image.Rect(0, 0, 8, 8), func() bool { return false },
+			func(image.Rectangle) (image.Image, error) { return nil, errBackend },
+			"backend failed",
+		},
+		{
+			"wrong frame size", image.Rect(0, 0, 8, 8), func() bool { return false },
+			func(image.Rectangle) (image.Image, error) { return testPage(4, 4), nil },
+			"captured frame is 4x4, expected 8x8",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captureRegionImage = tt.capture
+			_, err := CaptureScrolling(tt.region, tt.scrollFn, 0)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("err = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMatchOverlap(t *testing.T) {
+	page := testPage(4, 40)
+	frame := func(top int) *image.RGBA {
+		f := image.NewRGBA(image.Rect(0, 0, 4, 10))
+		copy(f.Pix, page.Pix[top*page.Stride:(top+10)*page.Stride])
+		return f
+	}
+
+	tests := []struct {
+		name             string
+		prevTop, nextTop int
+		maxOverlap       int
+		want             int
+	}{
+		{"three shared rows", 0, 7, 5, 3},
+		{"limited by maxOverlap", 0, 2, 5, 0},
+		{"exact maxOverlap", 0, 5, 5, 5},
+		{"no shared rows", 0, 10, 5, 0},
+		{"maxOverlap above height", 0, 1, 50, 9},
+	}
+	for _, tt := range tests {
+		if got := matchOverlap(frame(tt.prevTop), frame(tt.nextTop), tt.maxOverlap); got != tt.want {
+			t.Errorf("%s: matchOverlap = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package screen
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultWatchInterval is how often WatchDisplayChanges polls the display layout.
+const DefaultWatchInterval = 2 * time.Second
+
+// DisplayChangeEvent is emitted when the display layout changes, e.g. when a
+// monitor is connected or the user changes resolution or DPI scaling.
+type DisplayChangeEvent struct {
+	Previous []Display // Layout before the change
+	Current  []Display // Layout after the change
+	Time     time.Time // When the change was detected
+}
+
+// WatchDisplayChanges polls the display count, bounds, and scale factors every
+// interval (DefaultWatchInterval if <= 0) and emits an event whenever they change.
+// Long-running agents can use this to recalibrate coordinates after the user
+// changes resolution mid-session. The cached display layout is invalidated
+// before each event is emitted. The channel is closed when ctx is done.
+func WatchDisplayChanges(ctx context.Context, interval time.Duration) <-chan DisplayChangeEvent {
+	if interval <= 0 {
+		interval = DefaultWatchInterval
+	}
+
+	events := make(chan DisplayChangeEvent, 1)
+	go func() {
+		defer close(events)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		last := queryDisplays()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-ticker.C:
+				current := queryDisplays()
+				if sameDisplays(last, current) {
+					continue
+				}
+
+				invalidateDisplays()
+				event := DisplayChangeEvent{Previous: last, Current: current, Time: now}
+				last = current
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events
+}
@@ -0,0 +1,147 @@
+package screen
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeDisplays replaces the display backend with one that returns layouts in
+// turn, repeating the last one, and counts invalidations.
+func fakeDisplays(t *testing.T, layouts ...[]Display) *atomic.Int32 {
+	t.Helper()
+	var (
+		mu          sync.Mutex
+		calls       int
+		invalidated atomic.Int32
+	)
+	origQuery, origInvalidate := queryDisplays, invalidateDisplays
+	t.Cleanup(func() { queryDisplays, invalidateDisplays = origQuery, origInvalidate })
+
+	queryDisplays = func() []Display {
+		mu.Lock()
+		defer mu.Unlock()
+		i := calls
+		if i >= len(layouts) {
+			i = len(layouts) - 1
+		}
+		calls++
+		return layouts[i]
+	}
+	invalidateDisplays = func() { invalidated.Add(1) }
+	return &invalidated
+}
+
+func TestWatchDisplayChanges(t *testing.T) {
+	primary := Display{Index: 0, Width: 1920, Height: 1080, ScaleFactor: 1, IsPrimary: true}
+	scaled := Display{Index: 0, Width: 1920, Height: 1080, ScaleFactor: 2, IsPrimary: true}
+	resized := Display{Index: 0, Width: 2560, Height: 1440, ScaleFactor: 1, IsPrimary: true}
+	second := Display{Index: 1, X: 1920, Width: 1280, Height: 1024, ScaleFactor: 1}
+
+	tests := []struct {
+		name    string
+		layouts [][]Display
+		want    int // Expected change events
+	}{
+		{"stable", [][]Display{{primary}, {primary}, {primary}}, 0},
+		{"resolution change", [][]Display{{primary}, {resized}}, 1},
+		{"scale change", [][]Display{{primary}, {scaled}}, 1},
+		{"monitor connected", [][]Display{{primary}, {primary, second}}, 1},
+		{"connected then disconnected", [][]Display{{primary}, {primary, second}, {primary, second}, {primary}}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			invalidated := fakeDisplays(t, tt.layouts...)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			events := WatchDisplayChanges(ctx, time.Millisecond)
+			var got []DisplayChangeEvent
+			timeout := time.After(2 * time.Second)
+			for len(got) < tt.want {
+				select {
+				case ev := <-events:
+					got = append(got, ev)
+				case <-timeout:
+					t.Fatalf("got %d events, want %d", len(got), tt.want)
+				}
+			}
+
+			// Give the watcher time to report anything spurious before stopping it
+			time.Sleep(20 * time.Millisecond)
+			cancel()
+			for ev := range events {
+				got = append(got, ev)
+			}
+			if len(got) != tt.want {
+				t.Fatalf("got %d events, want %d", len(got), tt.want)
+			}
+			if n := int(invalidated.Load()); n != tt.want {
+				t.Errorf("cache invalidated %d times, want %d", n, tt.want)
+			}
+
+			// Each event links the previous layout to the next
+			for i, ev := range got {
+				if sameDisplays(ev.Previous, ev.Current) {
+					t.Errorf("event %d reports no change: %v", i, ev.Current)
+				}
+				if i > 0 && !sameDisplays(got[i-1].Current, ev.Previous) {
+					t.Errorf("event %d Previous %v, want %v", i, ev.Previous, got[i-1].Current)
+				}
+				if ev.Time.IsZero() {
+					t.Errorf("event %d has no time", i)
+				}
+			}
+		})
+	}
+}
+
+func TestWatchDisplayChangesClosesOnCancel(t *testing.T) {
+	fakeDisplays(t, []Display{{Index: 0, Width: 800, Height: 600}})
+	ctx, cancel := context.WithCancel(context.Background())
+	events := WatchDisplayChanges(ctx, time.Millisecond)
+	cancel()
+
+	select {
+	case _, ok := <-events:
+		if ok {
+			t.Error("unexpected event")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestRefreshDisplaysInvalidates(t *testing.T) {
+	invalidated := fakeDisplays(t, nil)
+	RefreshDisplays()
+	if n := invalidated.Load(); n != 1 {
+		t.Errorf("RefreshDisplays invalidated %d times, want 1", n)
+	}
+}
+
+func TestSameDisplays(t *testing.T) {
+	a := Display{Index: 0, Width: 1920, Height: 1080, ScaleFactor: 1, IsPrimary: true}
+	b := Display{Index: 1, X: 1920, Width: 1280, Height: 1024, ScaleFactor: 1}
+	moved := b
+	moved.X = -1280
+
+	tests := []struct {
+		name string
+		x, y []Display
+		want bool
+	}{
+		{"both empty", nil, nil, true},
+		{"equal", []Display{a, b}, []Display{a, b}, true},
+		{"different count", []Display{a}, []Display{a, b}, false},
+		{"different order", []Display{a, b}, []Display{b, a}, false},
+		{"moved display", []Display{a, b}, []Display{a, moved}, false},
+	}
+	for _, tt := range tests {
+		if got := sameDisplays(tt.x, tt.y); got != tt.want {
+			t.Errorf("%s: sameDisplays = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+// ErrPlanRejected is returned by runs whose plan was rejected by the approver
+// set with WithRequirePlanApproval.
+var ErrPlanRejected = errors.New("plan rejected")
+
+// PlanApprovalFunc approves the plan for a task before it runs.
+// It returns true to execute the task.
+type PlanApprovalFunc func(plan string) bool
+
+// planInstruction turns the agent's system prompt into a plan-only prompt.
+const planInstruction = `
+<plan_mode>
+PLAN ONLY. Do not call any tools and do not take any actions.
+List the numbered steps you intend to take to complete the task, naming the
+tool you would use for each step. Note any step that needs user confirmation.
+</plan_mode>`
+
+// ExplainPlan asks the model for the steps it intends to take for task,
+// without executing any tools, and returns the plan as text.
+// Use it to preview a task before running it, or see WithRequirePlanApproval.
+// The planning call's tokens count toward Usage as an LLM call, not a run.
+func (c *CUA) ExplainPlan(ctx context.Context, task string) (string, error) {
+	plan, _, err := c.explainPlan(ctx, task)
+	return plan, err
+}
+
+// explainPlan generates the plan for task and returns it along with the
+// planning call's usage, which is also added to the cumulative statistics.
+func (c *CUA) explainPlan(ctx context.Context, task string) (string, attemptTotals, error) {
+	toolNames := make([]string, len(c.tools))
+	for i, t := range c.tools {
+		toolNames[i] = t.Name()
+	}
+
+	start := time.Now()
+	prompt := fmt.Sprintf("Available tools: %s\n\nTask: %s", strings.Join(toolNames, ", "), task)
+	resp, err := c.llm.GenerateDetailed(ctx, prompt, interfaces.WithSystemMessage(c.systemPrompt+planInstruction))
+
+	totals := attemptTotals{llmCalls: 1, timeMs: time.Since(start).Milliseconds()}
+	if resp != nil && resp.Usage != nil {
+		totals.usage = &TokenUsage{
+			InputTokens:     resp.Usage.InputTokens,
+			OutputTokens:    resp.Usage.OutputTokens,
+			TotalTokens:     resp.Usage.TotalTokens,
+			ReasoningTokens: resp.Usage.ReasoningTokens,
+		}
+	}
+	c.usageStats.addCalls(totals.usage, totals.llmCalls, totals.timeMs)
+	c.checkTokenLimit()
+
+	if err != nil {
+		return "", totals, fmt.Errorf("failed to generate plan: %w", err)
+	}
+	return resp.Content, totals, nil
+}
+
+// approvePlan asks the configured approver to approve the plan for task. It
+// returns the planning call's usage so the run's Result can include it.
+func (c *CUA) approvePlan(ctx context.Context, task string) (attemptTotals, error) {
+	if c.config.PlanApproval == nil {
+		return attemptTotals{}, nil
+	}
+
+	plan, totals, err := c.explainPlan(ctx, task)
+	if err != nil {
+		return totals, err
+	}
+	if !c.config.PlanApproval(plan) {
+		return totals, ErrPlanRejected
+	}
+	return totals, nil
+}
@@ -0,0 +1,143 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+const testPlan = "1. app_launch Notes\n2. keyboard_type the note\n3. keyboard_press cmd+s"
+
+// newPlanCUA returns a CUA whose model answers with plan.
+func newPlanCUA(cfg *Config, llm *fakeLLM) *CUA {
+	c := newTestCUA(cfg)
+	c.llm = llm
+	c.systemPrompt = "SYSTEM"
+	c.tools = c.wrapTools([]interfaces.Tool{&fakeTool{name: "app_launch"}, &fakeTool{name: "keyboard_type"}})
+	return c
+}
+
+func TestExplainPlan(t *testing.T) {
+	llm := &fakeLLM{response: testPlan}
+	c := newPlanCUA(defaultConfig(), llm)
+
+	plan, err := c.ExplainPlan(context.Background(), "write a note")
+	if err != nil {
+		t.Fatalf("ExplainPlan: %v", err)
+	}
+	if plan != testPlan {
+		t.Errorf("plan = %q, want the model's answer verbatim", plan)
+	}
+	for _, want := range []string{"Task: write a note", "app_launch, keyboard_type"} {
+		if !strings.Contains(llm.prompt, want) {
+			t.Errorf("prompt %q does not mention %q", llm.prompt, want)
+		}
+	}
+	if !strings.HasPrefix(llm.system, "SYSTEM") || !strings.Contains(llm.system, "PLAN ONLY") {
+		t.Errorf("system message = %q, want the system prompt in plan mode", llm.system)
+	}
+}
+
+func TestExplainPlanError(t *testing.T) {
+	c := newPlanCUA(defaultConfig(), &fakeLLM{err: errors.New("overloaded")})
+	if _, err := c.ExplainPlan(context.Background(), "task"); err == nil || !strings.Contains(err.Error(), "overloaded") {
+		t.Errorf("err = %v, want the model error", err)
+	}
+}
+
+func TestApprovePlan(t *testing.T) {
+	tests := []struct {
+		name      string
+		approve   PlanApprovalFunc
+		wantErr   error
+		wantCalls int
+	}{
+		{"no approver skips planning", nil, nil, 0},
+		{"approved", func(string) bool { return true }, nil, 1},
+		{"rejected", func(string) bool { return false }, ErrPlanRejected, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			cfg := defaultConfig()
+			if tt.approve != nil {
+				WithRequirePlanApproval(func(plan string) bool {
+					seen = plan
+					return tt.approve(plan)
+				})(cfg)
+			}
+			llm := &fakeLLM{response: testPlan}
+			c := newPlanCUA(cfg, llm)
+
+			if _, err := c.approvePlan(context.Background(), "write a note"); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("approvePlan = %v, want %v", err, tt.wantErr)
+			}
+			if llm.calls != tt.wantCalls {
+				t.Errorf("model called %d times, want %d", llm.calls, tt.wantCalls)
+			}
+			if tt.approve != nil && seen != testPlan {
+				t.Errorf("approver saw %q, want the plan", seen)
+			}
+		})
+	}
+}
+
+func TestRunAbortsOnRejectedPlan(t *testing.T) {
+	cfg := defaultConfig()
+	WithRequirePlanApproval(func(string) bool { return false })(cfg)
+	tool := &fakeTool{name: "app_launch"}
+	c := newPlanCUA(cfg, &fakeLLM{response: testPlan})
+	c.tools = c.wrapTools([]interfaces.Tool{tool})
+
+	// The agent is nil, so reaching the run itself would panic
+	if _, err := c.Run(context.Background(), "write a note"); !errors.Is(err, ErrPlanRejected) {
+		t.Fatalf("Run = %v, want ErrPlanRejected", err)
+	}
+	if n := tool.calls.Load(); n != 0 {
+		t.Errorf("tool ran %d times after the plan was rejected", n)
+	}
+	if c.LastResult() != nil {
+		t.Error("a rejected run must not record a result")
+	}
+}
+
+func TestPlanUsage(t *testing.T) {
+	planUsage := &interfaces.TokenUsage{InputTokens: 100, OutputTokens: 20, TotalTokens: 120}
+
+	t.Run("ExplainPlan", func(t *testing.T) {
+		c := newPlanCUA(defaultConfig(), &fakeLLM{response: testPlan, usage: planUsage})
+		if _, err := c.ExplainPlan(context.Background(), "write a note"); err != nil {
+			t.Fatal(err)
+		}
+		got := c.Usage()
+		if got.TotalTokens != 120 || got.TotalLLMCalls != 1 || got.TotalRuns != 0 {
+			t.Errorf("Usage: %d tokens, %d LLM calls, %d runs; want the plan's 120 tokens as one LLM call and no run",
+				got.TotalTokens, got.TotalLLMCalls, got.TotalRuns)
+		}
+	})
+
+	t.Run("run with approval", func(t *testing.T) {
+		cfg := defaultConfig()
+		WithRequirePlanApproval(func(string) bool { return true })(cfg)
+		c := newPlanCUA(cfg, &fakeLLM{response: testPlan, usage: planUsage})
+		ag, err := c.newAgent(&scriptedLLM{})
+		if err != nil {
+			t.Fatalf("newAgent: %v", err)
+		}
+		c.agent = ag
+
+		if _, err := c.Run(context.Background(), "write a note"); err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		result := c.LastResult()
+		if result.Usage == nil || result.Usage.TotalTokens < 120 || result.LLMCalls < 1 {
+			t.Errorf("Result usage = %+v with %d LLM calls, want it to include the plan call", result.Usage, result.LLMCalls)
+		}
+		if got := c.Usage(); got.TotalTokens < 120 || got.TotalRuns != 1 {
+			t.Errorf("Usage: %d tokens, %d runs; want the plan's tokens and one run", got.TotalTokens, got.TotalRuns)
+		}
+	})
+}
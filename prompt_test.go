@@ -0,0 +1,34 @@
+package cua
+
+import (
+	"image"
+	"strings"
+	"testing"
+)
+
+func TestScreenshotScope(t *testing.T) {
+	region := image.Rect(100, 200, 600, 900)
+
+	tests := []struct {
+		name    string
+		region  *image.Rectangle
+		want    []string
+		notWant string
+	}{
+		{"full screen", nil, []string{"ENTIRE screen"}, "WORKING AREA"},
+		{"cropped", &region, []string{"WORKING AREA", "x 100-600, y 200-900", "relative to this working area"}, "ENTIRE screen."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := screenshotScope(tt.region)
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("scope %q does not mention %q", got, w)
+				}
+			}
+			if strings.Contains(got, tt.notWant) {
+				t.Errorf("scope %q mentions %q", got, tt.notWant)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package cua
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anxuanzi/cua/internal/coords"
+)
+
+func TestNewLLMClientLocal(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     Config
+		model   string
+		wantErr string
+	}{
+		{"model required", Config{Provider: ProviderLocal}, "", "model is required"},
+		{"default endpoint without key", Config{Provider: ProviderLocal}, "llama3.2-vision", ""},
+		{"custom endpoint", Config{Provider: ProviderLocal, BaseURL: "http://127.0.0.1:1234/v1"}, "qwen2.5-vl", ""},
+		{"unsupported provider", Config{Provider: "bogus"}, "m", "unsupported provider"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := newLLMClient(&tt.cfg, tt.model)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if client == nil {
+				t.Fatal("client is nil")
+			}
+		})
+	}
+}
+
+func TestPlatformPromptContext(t *testing.T) {
+	tests := []struct {
+		platform string
+		want     string
+	}{
+		{"darwin", "Cmd"},
+		{"windows", "Ctrl"},
+		{"linux", "Super"},
+		{"plan9", "OS: Unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.platform, func(t *testing.T) {
+			got := platformPromptContext(tt.platform)
+			if !strings.Contains(got, tt.want) {
+				t.Errorf("context for %s does not mention %q", tt.platform, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateTextOnlyPrompt(t *testing.T) {
+	screen := coords.ScreenInfo{Index: 1, Width: 1920, Height: 1080, ScaleFactor: 2}
+	now := time.Date(2026, 1, 2, 15, 4, 0, 0, time.UTC)
+
+	got := generateTextOnlyPrompt("<platform_config>test</platform_config>", now, screen)
+
+	for _, w := range []string{"CANNOT see the screen", "1920x1080", "index: 1", "<platform_config>test</platform_config>"} {
+		if !strings.Contains(got, w) {
+			t.Errorf("prompt does not mention %q", w)
+		}
+	}
+	if strings.Contains(got, "- screen_capture") {
+		t.Error("text-only prompt must not offer screen_capture")
+	}
+}
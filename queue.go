@@ -0,0 +1,81 @@
+package cua
+
+import (
+	"context"
+	"sync"
+)
+
+// runQueue serializes task execution in submission order.
+// It is used when queueing is enabled via WithQueueing so that concurrent
+// Run/RunDetailed/RunStream calls execute one at a time instead of sharing
+// the desktop simultaneously.
+type runQueue struct {
+	mu      sync.Mutex
+	busy    bool
+	closed  bool
+	waiters []chan error // each receives nil on ownership or ErrClosed
+}
+
+// acquire blocks until the caller owns the queue or ctx is done.
+// Callers are granted ownership in the order they called acquire.
+// It returns ErrClosed if the queue is closed before the caller's turn.
+func (q *runQueue) acquire(ctx context.Context) error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrClosed
+	}
+	if !q.busy {
+		q.busy = true
+		q.mu.Unlock()
+		return nil
+	}
+	ready := make(chan error, 1)
+	q.waiters = append(q.waiters, ready)
+	q.mu.Unlock()
+
+	select {
+	case err := <-ready:
+		return err
+	case <-ctx.Done():
+		q.mu.Lock()
+		for i, w := range q.waiters {
+			if w == ready {
+				q.waiters = append(q.waiters[:i], q.waiters[i+1:]...)
+				q.mu.Unlock()
+				return ctx.Err()
+			}
+		}
+		q.mu.Unlock()
+		// Ownership was handed to us while we were cancelling - pass it on
+		if err := <-ready; err == nil {
+			q.release()
+		}
+		return ctx.Err()
+	}
+}
+
+// release hands ownership to the next waiter, or marks the queue idle.
+func (q *runQueue) release() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if len(q.waiters) == 0 {
+		q.busy = false
+		return
+	}
+	next := q.waiters[0]
+	q.waiters = q.waiters[1:]
+	next <- nil
+}
+
+// close rejects all current and future waiters with ErrClosed. The current
+// owner keeps the queue until it releases it.
+func (q *runQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.closed = true
+	for _, w := range q.waiters {
+		w <- ErrClosed
+	}
+	q.waiters = nil
+}
@@ -0,0 +1,176 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// waitForWaiters blocks until q has n queued waiters.
+func waitForWaiters(t *testing.T, q *runQueue, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		q.mu.Lock()
+		got := len(q.waiters)
+		q.mu.Unlock()
+		if got == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("queue has %d waiters, want %d", got, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestRunQueueOrder(t *testing.T) {
+	q := &runQueue{}
+	ctx := context.Background()
+	if err := q.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 5
+	var (
+		mu    sync.Mutex
+		order []int
+		wg    sync.WaitGroup
+	)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if err := q.acquire(ctx); err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+			q.release()
+		}(i)
+		// Enqueue one at a time so submission order is known
+		waitForWaiters(t, q, i+1)
+	}
+
+	q.release()
+	wg.Wait()
+
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("ran in order %v, want submission order", order)
+		}
+	}
+	if q.busy {
+		t.Error("queue still busy after all runs released it")
+	}
+}
+
+func TestRunQueueMutualExclusion(t *testing.T) {
+	q := &runQueue{}
+	ctx := context.Background()
+
+	var active atomic.Int32
+	var overlapped atomic.Bool
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := q.acquire(ctx); err != nil {
+				t.Error(err)
+				return
+			}
+			if active.Add(1) > 1 {
+				overlapped.Store(true)
+			}
+			time.Sleep(time.Millisecond)
+			active.Add(-1)
+			q.release()
+		}()
+	}
+	wg.Wait()
+
+	if overlapped.Load() {
+		t.Error("two runs held the queue at once")
+	}
+}
+
+func TestRunQueueCancelledWaiter(t *testing.T) {
+	q := &runQueue{}
+	if err := q.acquire(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelled := make(chan error, 1)
+	go func() { cancelled <- q.acquire(ctx) }()
+	waitForWaiters(t, q, 1)
+
+	next := make(chan error, 1)
+	go func() { next <- q.acquire(context.Background()) }()
+	waitForWaiters(t, q, 2)
+
+	cancel()
+	if err := <-cancelled; !errors.Is(err, context.Canceled) {
+		t.Fatalf("cancelled waiter got %v, want context.Canceled", err)
+	}
+	waitForWaiters(t, q, 1)
+
+	// The cancelled waiter must not hold up the one behind it
+	q.release()
+	select {
+	case err := <-next:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("next waiter never acquired the queue")
+	}
+	q.release()
+	if q.busy {
+		t.Error("queue still busy")
+	}
+}
+
+func TestRunQueueIdleAfterRelease(t *testing.T) {
+	q := &runQueue{}
+	for i := 0; i < 3; i++ {
+		if err := q.acquire(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		q.release()
+	}
+	if q.busy || len(q.waiters) != 0 {
+		t.Errorf("queue busy=%v waiters=%d, want idle", q.busy, len(q.waiters))
+	}
+}
+
+func TestRunQueueClose(t *testing.T) {
+	q := &runQueue{}
+	ctx := context.Background()
+	if err := q.acquire(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { errs <- q.acquire(ctx) }()
+	}
+	waitForWaiters(t, q, 2)
+
+	q.close()
+	for i := 0; i < 2; i++ {
+		if err := <-errs; !errors.Is(err, ErrClosed) {
+			t.Errorf("queued acquire = %v, want ErrClosed", err)
+		}
+	}
+	if err := q.acquire(ctx); !errors.Is(err, ErrClosed) {
+		t.Errorf("acquire after close = %v, want ErrClosed", err)
+	}
+	q.release()
+}
@@ -0,0 +1,109 @@
+package cua
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/agent"
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+
+	"github.com/anxuanzi/cua/internal/tools"
+)
+
+// PanicError is a panic recovered from a tool or the agent loop. One bad
+// action returns an error instead of crashing the caller's process.
+type PanicError struct {
+	// Tool is the tool that panicked, or "" if the panic was in the agent loop.
+	Tool string
+	// Value is the value passed to panic.
+	Value interface{}
+	// Stack is the goroutine stack at the time of the panic.
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	if e.Tool == "" {
+		return fmt.Sprintf("agent panicked: %v", e.Value)
+	}
+	return fmt.Sprintf("tool %s panicked: %v", e.Tool, e.Value)
+}
+
+// PanicRecord is a recovered panic as recorded in a Result.
+type PanicRecord struct {
+	// Tool is the tool that panicked, or "" if the panic was in the agent loop.
+	Tool string `json:"tool,omitempty"`
+	// Value is the value passed to panic, formatted with %v.
+	Value string `json:"value"`
+	// Stack is the goroutine stack at the time of the panic.
+	Stack string `json:"stack"`
+}
+
+// panicLogKey is the context key for the run's panic log.
+type panicLogKey struct{}
+
+// panicLog collects the panics recovered during a run, across fallback attempts.
+type panicLog struct {
+	mu      sync.Mutex
+	records []PanicRecord
+}
+
+// withPanicLog attaches an empty panic log to ctx.
+func withPanicLog(ctx context.Context) context.Context {
+	return context.WithValue(ctx, panicLogKey{}, &panicLog{})
+}
+
+// recordPanic adds p to the run's panic log, if ctx has one, so its stack
+// reaches the Result (and ResultStore) whether or not a tracer is configured.
+func recordPanic(ctx context.Context, p *PanicError) {
+	log, _ := ctx.Value(panicLogKey{}).(*panicLog)
+	if log == nil {
+		return
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	log.records = append(log.records, PanicRecord{
+		Tool:  p.Tool,
+		Value: fmt.Sprint(p.Value),
+		Stack: string(p.Stack),
+	})
+}
+
+// panicsFrom returns the panics recorded in ctx's panic log, or nil.
+func panicsFrom(ctx context.Context) []PanicRecord {
+	log, _ := ctx.Value(panicLogKey{}).(*panicLog)
+	if log == nil {
+		return nil
+	}
+	log.mu.Lock()
+	defer log.mu.Unlock()
+	return append([]PanicRecord(nil), log.records...)
+}
+
+// runAgent runs the agent, converting a panic into a *PanicError.
+func (c *CUA) runAgent(ctx context.Context, ag *agent.Agent, task string) (resp *interfaces.AgentResponse, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr := &PanicError{Value: r, Stack: debug.Stack()}
+			recordPanic(ctx, panicErr)
+			resp, err = nil, panicErr
+		}
+	}()
+	return ag.RunDetailed(ctx, task)
+}
+
+// executeToolSafely executes a tool call, converting a panic into a *PanicError.
+// Panics are reported to the model as an error observation so the run can continue.
+func (c *CUA) executeToolSafely(ctx context.Context, tool interfaces.Tool, argsJSON string) (result string, panicErr *PanicError, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			panicErr = &PanicError{Tool: tool.Name(), Value: r, Stack: debug.Stack()}
+			recordPanic(ctx, panicErr)
+			result = tools.ErrorResponse(panicErr.Error(), "This action failed unexpectedly. Try a different approach.")
+			err = nil
+		}
+	}()
+	result, err = c.executeTool(ctx, tool, argsJSON)
+	return result, nil, err
+}
@@ -0,0 +1,60 @@
+package cua
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestExecuteToolSafelyRecordsPanics(t *testing.T) {
+	c := newTestCUA(defaultConfig())
+	ctx := withRunState(withPanicLog(context.Background()))
+
+	tests := []struct {
+		name      string
+		tool      *fakeTool
+		wantPanic bool
+	}{
+		{"ok", &fakeTool{name: "mouse_click"}, false},
+		{"string panic", &fakeTool{name: "mouse_click", panics: "boom"}, true},
+		{"error panic", &fakeTool{name: "keyboard_type", panics: errors.New("bad state")}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, panicErr, err := c.executeToolSafely(ctx, tt.tool, "{}")
+			if err != nil {
+				t.Fatalf("err = %v, want nil", err)
+			}
+			if got := panicErr != nil; got != tt.wantPanic {
+				t.Fatalf("panicked = %v, want %v", got, tt.wantPanic)
+			}
+			if tt.wantPanic && !strings.Contains(result, "panicked") {
+				t.Errorf("result %s does not report the panic", result)
+			}
+		})
+	}
+
+	panics := panicsFrom(ctx)
+	if len(panics) != 2 {
+		t.Fatalf("recorded %d panics, want 2", len(panics))
+	}
+	want := []PanicRecord{{Tool: "mouse_click", Value: "boom"}, {Tool: "keyboard_type", Value: "bad state"}}
+	for i, p := range panics {
+		if p.Tool != want[i].Tool || p.Value != want[i].Value {
+			t.Errorf("panic %d = %s/%s, want %s/%s", i, p.Tool, p.Value, want[i].Tool, want[i].Value)
+		}
+		if !strings.Contains(p.Stack, "goroutine") {
+			t.Errorf("panic %d has no stack: %q", i, p.Stack)
+		}
+	}
+}
+
+func TestRecordPanicWithoutLog(t *testing.T) {
+	// Outside a run (e.g., ExecuteTool) there is no log; recording is a no-op
+	ctx := context.Background()
+	recordPanic(ctx, &PanicError{Value: "boom"})
+	if got := panicsFrom(ctx); got != nil {
+		t.Errorf("panicsFrom = %v, want nil", got)
+	}
+}
@@ -0,0 +1,208 @@
+package cua
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anxuanzi/cua/internal/tools"
+	"github.com/anxuanzi/cua/pkg/screen"
+)
+
+// ResultReason explains how a run ended.
+type ResultReason string
+
+const (
+	// ReasonCompleted means the run ended without error with a final answer.
+	// agent-sdk-go also ends a run that reaches MaxIterations with a final
+	// answer, so such runs are reported as completed too.
+	ReasonCompleted ResultReason = "completed"
+	// ReasonCancelled means the run's context was cancelled (e.g., by Stop).
+	ReasonCancelled ResultReason = "cancelled"
+	// ReasonTimeout means the run's context deadline was exceeded.
+	ReasonTimeout ResultReason = "timeout"
+	// ReasonRateLimited means the provider rejected a request for rate limiting.
+	ReasonRateLimited ResultReason = "rate_limited"
+	// ReasonMaxActions means the run reached its tool call limit (see WithMaxActions).
+	ReasonMaxActions ResultReason = "max_actions"
+	// ReasonNeedsHelp means the model stopped to ask a human for help (see WithHelpRequests).
+	ReasonNeedsHelp ResultReason = "needs_help"
+	// ReasonPlanRejected means the plan approver rejected the run's plan.
+	ReasonPlanRejected ResultReason = "plan_rejected"
+	// ReasonPanic means a tool or the agent loop panicked.
+	ReasonPanic ResultReason = "panic"
+	// ReasonProviderError means the run failed with any other error.
+	ReasonProviderError ResultReason = "provider_error"
+)
+
+// ErrMaxActions is returned by runs stopped for reaching the tool call limit
+// set with WithMaxActions.
+var ErrMaxActions = errors.New("max actions reached")
+
+// NeedsHelpError is returned by runs the model stopped to ask a human for
+// help (see WithHelpRequests).
+type NeedsHelpError struct {
+	Reason string // The model's explanation of what it needs help with
+}
+
+func (e *NeedsHelpError) Error() string {
+	return "agent needs help: " + e.Reason
+}
+
+// Result is the record of a single run, as returned by LastResult and saved to a ResultStore.
+type Result struct {
+	// Task is the task the agent was given.
+	Task string `json:"task"`
+	// Response is the agent's final response (possibly partial on error).
+	Response string `json:"response"`
+	// Error is the run error, if the run failed.
+	Error string `json:"error,omitempty"`
+	// Success reports whether the run completed without error.
+	Success bool `json:"success"`
+	// Reason explains how the run ended, so callers can branch without
+	// matching error strings.
+	Reason ResultReason `json:"reason"`
+
+	// Provider and Model identify the LLM that performed the run (the last
+	// fallback model tried, if the run escalated).
+	Provider LLMProvider `json:"provider"`
+	Model    string      `json:"model,omitempty"`
+	// Escalations is the number of fallback models the run escalated to.
+	Escalations int `json:"escalations,omitempty"`
+
+	// OrgID and ConversationID identify the run's tenant and conversation.
+	OrgID          string `json:"org_id,omitempty"`
+	ConversationID string `json:"conversation_id,omitempty"`
+
+	// StartedAt is when the run started.
+	StartedAt time.Time `json:"started_at"`
+	// DurationMs is the run's execution time in milliseconds, across all attempts.
+	DurationMs int64 `json:"duration_ms"`
+
+	// Usage is the run's token usage across all attempts, if reported by the provider.
+	Usage     *TokenUsage `json:"usage,omitempty"`
+	LLMCalls  int         `json:"llm_calls"`
+	ToolCalls int         `json:"tool_calls"`
+
+	// Panics lists the panics recovered during the run, in order, with their
+	// stacks. A tool panic doesn't fail the run, so check this for crashes.
+	Panics []PanicRecord `json:"panics,omitempty"`
+
+	// FailureScreenshot is a JPEG of the screen when the run failed, captured
+	// when WithCaptureOnFailure is enabled. Encoded as base64 in JSON.
+	FailureScreenshot []byte `json:"failure_screenshot,omitempty"`
+}
+
+// ResultStore persists run results for auditing and analytics.
+// Save is called after each Run/RunDetailed, including failed runs.
+// Implementations must be safe for concurrent use.
+type ResultStore interface {
+	Save(ctx context.Context, result *Result) error
+}
+
+// LastResult returns the result of the most recent Run/RunDetailed call, or nil.
+func (c *CUA) LastResult() *Result {
+	c.resultMu.Lock()
+	defer c.resultMu.Unlock()
+	return c.lastResult
+}
+
+// recordResult remembers result as the last result and saves it to the store.
+func (c *CUA) recordResult(ctx context.Context, result *Result) {
+	c.resultMu.Lock()
+	c.lastResult = result
+	c.resultMu.Unlock()
+
+	if c.config.ResultStore != nil {
+		// Persisting is best-effort and must not fail the run; save even if
+		// the run was cancelled so failures are recorded too
+		_ = c.config.ResultStore.Save(context.WithoutCancel(ctx), result)
+	}
+}
+
+// captureFailureJPEG captures a screen as JPEG for failed runs.
+// It is a variable so failure capture can be driven by a fake capture.
+var captureFailureJPEG = screen.CaptureJPEGBytes
+
+// captureFailureScreenshot captures the configured screen as a JPEG sized like
+// the screenshots sent to the model. It returns nil if the capture fails.
+func (c *CUA) captureFailureScreenshot() []byte {
+	frame, _, err := captureFailureJPEG(c.config.ScreenIndex, tools.DefaultJPEGQuality, tools.MaxScreenshotWidth)
+	if err != nil {
+		return nil
+	}
+	return frame
+}
+
+// resultReason classifies how a run ended from its error.
+func resultReason(err error) ResultReason {
+	var needsHelp *NeedsHelpError
+	var panicErr *PanicError
+	switch {
+	case err == nil:
+		return ReasonCompleted
+	case errors.Is(err, ErrMaxActions):
+		return ReasonMaxActions
+	case errors.As(err, &needsHelp):
+		return ReasonNeedsHelp
+	case errors.Is(err, ErrPlanRejected):
+		return ReasonPlanRejected
+	case errors.As(err, &panicErr):
+		return ReasonPanic
+	case errors.Is(err, context.Canceled), errors.Is(err, ErrClosed):
+		return ReasonCancelled
+	case errors.Is(err, context.DeadlineExceeded):
+		return ReasonTimeout
+	}
+
+	// Providers report rate limits only in the error text
+	msg := strings.ToLower(err.Error())
+	if strings.Contains(msg, "429") || strings.Contains(msg, "rate limit") || strings.Contains(msg, "rate_limit") {
+		return ReasonRateLimited
+	}
+	return ReasonProviderError
+}
+
+// JSONLResultStore is a ResultStore that appends each result as one JSON line to a file.
+type JSONLResultStore struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+// NewJSONLResultStore opens (or creates) path for appending run results.
+// Call Close when the store is no longer needed.
+func NewJSONLResultStore(path string) (*JSONLResultStore, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open result store: %w", err)
+	}
+	return &JSONLResultStore{file: f}, nil
+}
+
+// Save appends result as a single JSON line.
+func (s *JSONLResultStore) Save(_ context.Context, result *Result) error {
+	line, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal result: %w", err)
+	}
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.file.Write(line); err != nil {
+		return fmt.Errorf("failed to write result: %w", err)
+	}
+	return nil
+}
+
+// Close closes the underlying file.
+func (s *JSONLResultStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.file.Close()
+}
@@ -0,0 +1,274 @@
+package cua
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+	"github.com/anxuanzi/cua/internal/tools"
+	"github.com/anxuanzi/cua/pkg/screen"
+)
+
+func TestResultReason(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want ResultReason
+	}{
+		{"completed", nil, ReasonCompleted},
+		{"cancelled", context.Canceled, ReasonCancelled},
+		{"cancelled wrapped", fmt.Errorf("agent run: %w", context.Canceled), ReasonCancelled},
+		{"timeout", context.DeadlineExceeded, ReasonTimeout},
+		{"timeout wrapped", fmt.Errorf("agent run: %w", context.DeadlineExceeded), ReasonTimeout},
+		{"rate limited status", errors.New("gemini: error 429: resource exhausted"), ReasonRateLimited},
+		{"rate limited text", errors.New("Rate limit exceeded, retry later"), ReasonRateLimited},
+		{"rate limited code", errors.New(`{"type":"rate_limit_error"}`), ReasonRateLimited},
+		{"provider error", errors.New("invalid api key"), ReasonProviderError},
+		{"panic", &PanicError{Value: "boom"}, ReasonPanic},
+		{"panic wrapped", fmt.Errorf("attempt: %w", &PanicError{Value: "boom"}), ReasonPanic},
+		{"plan rejected", ErrPlanRejected, ReasonPlanRejected},
+		{"max actions", ErrMaxActions, ReasonMaxActions},
+		{"needs help", &NeedsHelpError{Reason: "captcha"}, ReasonNeedsHelp},
+		{"closed", ErrClosed, ReasonCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := resultReason(tt.err); got != tt.want {
+				t.Errorf("resultReason(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// spyStore is a ResultStore that records what it is asked to save.
+type spyStore struct {
+	mu      sync.Mutex
+	results []*Result
+	ctxErrs []error
+}
+
+func (s *spyStore) Save(ctx context.Context, result *Result) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results = append(s.results, result)
+	s.ctxErrs = append(s.ctxErrs, ctx.Err())
+	return errors.New("store unavailable")
+}
+
+func TestRecordResultSavesToStore(t *testing.T) {
+	store := &spyStore{}
+	cfg := defaultConfig()
+	WithResultStore(store)(cfg)
+	c := newTestCUA(cfg)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	result := &Result{Task: "open notes", Success: false, Reason: ReasonCancelled}
+	c.recordResult(ctx, result)
+
+	if c.LastResult() != result {
+		t.Error("LastResult does not return the recorded result")
+	}
+	if len(store.results) != 1 || store.results[0] != result {
+		t.Fatalf("store received %v, want the recorded result", store.results)
+	}
+	if store.ctxErrs[0] != nil {
+		t.Errorf("store saw a cancelled context (%v); failed runs must still be saved", store.ctxErrs[0])
+	}
+}
+
+func TestJSONLResultStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "runs.jsonl")
+	store, err := NewJSONLResultStore(path)
+	if err != nil {
+		t.Fatalf("NewJSONLResultStore: %v", err)
+	}
+
+	runs := []*Result{
+		{Task: "first", Response: "done", Success: true, Reason: ReasonCompleted, ToolCalls: 3},
+		{Task: "second", Error: "boom\nwith newline", Reason: ReasonProviderError},
+	}
+	for _, r := range runs {
+		if err := store.Save(context.Background(), r); err != nil {
+			t.Fatalf("Save: %v", err)
+		}
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// Reopening appends rather than truncating
+	store, err = NewJSONLResultStore(path)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	_ = store.Save(context.Background(), &Result{Task: "third"})
+	_ = store.Close()
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var tasks []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var r Result
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			t.Fatalf("line %q is not a result: %v", scanner.Text(), err)
+		}
+		tasks = append(tasks, r.Task)
+	}
+	if fmt.Sprint(tasks) != "[first second third]" {
+		t.Errorf("tasks = %v, want [first second third]", tasks)
+	}
+}
+
+func TestCaptureOnFailure(t *testing.T) {
+	orig := captureFailureJPEG
+	t.Cleanup(func() { captureFailureJPEG = orig })
+	frame := []byte{0xff, 0xd8, 0xff}
+	var captures int
+	captureFailureJPEG = func(int, int, int) ([]byte, screen.Dimensions, error) {
+		captures++
+		return frame, screen.Dimensions{Width: 4, Height: 3}, nil
+	}
+
+	tests := []struct {
+		name    string
+		enabled bool
+		llmErr  error
+		want    bool
+	}{
+		{"disabled", false, errors.New("invalid api key"), false},
+		{"enabled failure", true, errors.New("invalid api key"), true},
+		{"enabled success", true, nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			captures = 0
+			cfg := defaultConfig()
+			WithCaptureOnFailure(tt.enabled)(cfg)
+			c := newTestCUA(cfg)
+			c.tools = c.wrapTools([]interfaces.Tool{&fakeTool{name: "mouse_click"}})
+			ag, err := c.newAgent(&fakeLLM{response: "done", err: tt.llmErr})
+			if err != nil {
+				t.Fatalf("newAgent: %v", err)
+			}
+			c.agent = ag
+
+			_, result, err := c.runDetailed(context.Background(), "open notes")
+			if (err != nil) != (tt.llmErr != nil) {
+				t.Fatalf("runDetailed error = %v, want error %v", err, tt.llmErr != nil)
+			}
+			if got := len(result.FailureScreenshot) > 0; got != tt.want {
+				t.Errorf("FailureScreenshot set = %v, want %v", got, tt.want)
+			}
+			if tt.want && (captures != 1 || string(result.FailureScreenshot) != string(frame)) {
+				t.Errorf("captured %d times with %v, want the fake frame once", captures, result.FailureScreenshot)
+			}
+		})
+	}
+}
+
+func TestCaptureFailureScreenshotError(t *testing.T) {
+	orig := captureFailureJPEG
+	t.Cleanup(func() { captureFailureJPEG = orig })
+	captureFailureJPEG = func(int, int, int) ([]byte, screen.Dimensions, error) {
+		return nil, screen.Dimensions{}, errors.New("no display")
+	}
+
+	c := newTestCUA(defaultConfig())
+	if got := c.captureFailureScreenshot(); got != nil {
+		t.Errorf("captureFailureScreenshot = %v, want nil when capture fails", got)
+	}
+}
+
+// toolCall is a tool call made by scriptedLLM.
+type toolCall struct {
+	tool, args string
+}
+
+// scriptedLLM is a model that makes a fixed sequence of tool calls, like a
+// provider client's tool loop, and then answers "done". It stops with the
+// context's error once the run is cancelled.
+type scriptedLLM struct {
+	fakeLLM
+	script []toolCall
+}
+
+func (m *scriptedLLM) GenerateWithTools(ctx context.Context, _ string, toolList []interfaces.Tool, _ ...interfaces.GenerateOption) (string, error) {
+	for _, call := range m.script {
+		if err := ctx.Err(); err != nil {
+			return "", err
+		}
+		for _, tool := range toolList {
+			if tool.Name() == call.tool {
+				_, _ = tool.Execute(ctx, call.args)
+			}
+		}
+	}
+	return "done", nil
+}
+
+func (m *scriptedLLM) GenerateWithToolsDetailed(ctx context.Context, prompt string, toolList []interfaces.Tool, options ...interfaces.GenerateOption) (*interfaces.LLMResponse, error) {
+	content, err := m.GenerateWithTools(ctx, prompt, toolList, options...)
+	return &interfaces.LLMResponse{Content: content}, err
+}
+
+func TestRunStopReasons(t *testing.T) {
+	click := toolCall{"mouse_click", `{"x": 500, "y": 500}`}
+	tests := []struct {
+		name       string
+		opts       []Option
+		script     []toolCall
+		wantReason ResultReason
+		wantClicks int32
+		wantErr    func(error) bool
+	}{
+		{"completed", nil, []toolCall{click, click}, ReasonCompleted, 2,
+			func(err error) bool { return err == nil }},
+		{"max actions", []Option{WithMaxActions(2)}, []toolCall{click, click, click, click}, ReasonMaxActions, 2,
+			func(err error) bool { return errors.Is(err, ErrMaxActions) }},
+		{"needs help", []Option{WithHelpRequests(true)}, []toolCall{click, {"request_help", `{"reason": "captcha"}`}, click}, ReasonNeedsHelp, 1,
+			func(err error) bool {
+				var help *NeedsHelpError
+				return errors.As(err, &help) && help.Reason == "captcha"
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			for _, opt := range tt.opts {
+				opt(cfg)
+			}
+			c := newTestCUA(cfg)
+			fake := &fakeTool{name: "mouse_click"}
+			c.tools = c.wrapTools([]interfaces.Tool{fake, tools.NewHelpTool()})
+			ag, err := c.newAgent(&scriptedLLM{script: tt.script})
+			if err != nil {
+				t.Fatalf("newAgent: %v", err)
+			}
+			c.agent = ag
+
+			_, result, err := c.runDetailed(context.Background(), "open notes")
+			if !tt.wantErr(err) {
+				t.Errorf("runDetailed error = %v", err)
+			}
+			if result.Reason != tt.wantReason {
+				t.Errorf("Reason = %q, want %q", result.Reason, tt.wantReason)
+			}
+			if got := fake.calls.Load(); got != tt.wantClicks {
+				t.Errorf("clicked %d times, want %d", got, tt.wantClicks)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package cua
+
+import (
+	"context"
+	"sync"
+
+	"github.com/anxuanzi/cua/internal/tools"
+)
+
+// runStateKey is the context key for the per-run state.
+type runStateKey struct{}
+
+// runState holds state scoped to a single run, shared by its tool calls.
+type runState struct {
+	mu           sync.Mutex
+	toolCalls    int  // Tool calls executed so far
+	budgetWarned bool // Whether the tool call budget warning has fired
+
+	cancel  context.CancelFunc // Cancels the run's context
+	stopped error              // Why the run was stopped by stopRun, if it was
+}
+
+// withRunState attaches fresh per-run state to ctx, including the tools'
+// per-run state (screenshot caches and the last pointer action). The returned
+// context is cancelled when the run is stopped by stopRun.
+func withRunState(ctx context.Context) context.Context {
+	ctx, cancel := context.WithCancel(tools.WithRunState(ctx))
+	return context.WithValue(ctx, runStateKey{}, &runState{cancel: cancel})
+}
+
+// runStateFrom returns the per-run state, or nil outside a run (e.g., ExecuteTool).
+func runStateFrom(ctx context.Context) *runState {
+	state, _ := ctx.Value(runStateKey{}).(*runState)
+	return state
+}
+
+// stopRun ends the run in ctx early, making it fail with cause (see
+// stopCause). It is a no-op outside a run.
+func stopRun(ctx context.Context, cause error) {
+	state := runStateFrom(ctx)
+	if state == nil {
+		return
+	}
+	state.mu.Lock()
+	if state.stopped == nil {
+		state.stopped = cause
+	}
+	state.mu.Unlock()
+	state.cancel()
+}
+
+// stopCause returns the cause passed to stopRun in place of err if the run in
+// ctx was stopped and failed, so it doesn't surface as a plain cancellation.
+func stopCause(ctx context.Context, err error) error {
+	state := runStateFrom(ctx)
+	if err == nil || state == nil {
+		return err
+	}
+	state.mu.Lock()
+	defer state.mu.Unlock()
+	if state.stopped != nil {
+		return state.stopped
+	}
+	return err
+}
+
+// recordToolCall counts an executed tool call and fires the tool call budget
+// warning the first time the count reaches the configured threshold of
+// ToolCallBudget. It returns ErrMaxActions, without counting the call, if the
+// run has already executed MaxActions tool calls.
+func (c *CUA) recordToolCall(ctx context.Context) error {
+	state := runStateFrom(ctx)
+	if state == nil {
+		return nil
+	}
+
+	state.mu.Lock()
+	if c.config.MaxActions > 0 && state.toolCalls >= c.config.MaxActions {
+		state.mu.Unlock()
+		return ErrMaxActions
+	}
+	state.toolCalls++
+	used := state.toolCalls
+	limit := c.config.ToolCallBudget
+	fire := false
+	if c.config.OnToolCallBudgetWarning != nil && limit > 0 && !state.budgetWarned {
+		threshold := c.config.ToolCallBudgetWarningThreshold
+		if threshold <= 0 {
+			threshold = 80 // Default 80%
+		}
+		if float64(used)/float64(limit)*100 >= float64(threshold) {
+			state.budgetWarned = true
+			fire = true
+		}
+	}
+	state.mu.Unlock()
+
+	if fire {
+		c.config.OnToolCallBudgetWarning(used, limit, float64(used)/float64(limit)*100)
+	}
+	return nil
+}
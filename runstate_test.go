@@ -0,0 +1,89 @@
+package cua
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestToolCallBudgetWarning(t *testing.T) {
+	type warning struct {
+		used, limit int
+		pct         float64
+	}
+	var warnings []warning
+
+	cfg := defaultConfig()
+	WithToolCallBudgetWarning(10, 50, func(used, limit int, pct float64) {
+		warnings = append(warnings, warning{used, limit, pct})
+	})(cfg)
+	c := newTestCUA(cfg)
+	tool := &fakeTool{name: "mouse_click"}
+	ctx := withRunState(context.Background())
+
+	for i := 0; i < 8; i++ {
+		if _, err := c.executeTool(ctx, tool, "{}"); err != nil {
+			t.Fatalf("executeTool: %v", err)
+		}
+	}
+
+	if len(warnings) != 1 {
+		t.Fatalf("got %d warnings, want exactly 1: %v", len(warnings), warnings)
+	}
+	if got, want := warnings[0], (warning{5, 10, 50}); got != want {
+		t.Errorf("warning = %+v, want %+v", got, want)
+	}
+}
+
+func TestToolCallBudgetIgnoresRejectedCalls(t *testing.T) {
+	fired := false
+	cfg := defaultConfig()
+	WithToolCallBudgetWarning(2, 100, func(int, int, float64) { fired = true })(cfg)
+	WithActionFilter(func(string, map[string]any) (FilterDecision, map[string]any) {
+		return FilterDeny, nil
+	})(cfg)
+	c := newTestCUA(cfg)
+	ctx := withRunState(context.Background())
+
+	denied := &fakeTool{name: "mouse_click"}
+	for i := 0; i < 3; i++ {
+		_, _ = c.executeTool(ctx, denied, "{}")
+	}
+
+	cfg.ActionFilter = nil
+	invalid := &fakeTool{name: "mouse_click", params: map[string]interfaces.ParameterSpec{
+		"x": {Type: "integer", Required: true},
+	}}
+	for i := 0; i < 3; i++ {
+		_, _ = c.executeTool(ctx, invalid, "{}")
+	}
+
+	if fired {
+		t.Error("budget warning fired for calls that never executed")
+	}
+	if got := runStateFrom(ctx).toolCalls; got != 0 {
+		t.Errorf("toolCalls = %d, want 0", got)
+	}
+	if denied.calls.Load() != 0 || invalid.calls.Load() != 0 {
+		t.Error("rejected calls reached the tool")
+	}
+}
+
+func TestToolCallBudgetPerRun(t *testing.T) {
+	fired := 0
+	cfg := defaultConfig()
+	WithToolCallBudgetWarning(1, 100, func(int, int, float64) { fired++ })(cfg)
+	c := newTestCUA(cfg)
+	tool := &fakeTool{name: "mouse_click"}
+
+	for run := 0; run < 2; run++ {
+		ctx := withRunState(context.Background())
+		_, _ = c.executeTool(ctx, tool, "{}")
+		_, _ = c.executeTool(ctx, tool, "{}")
+	}
+
+	if fired != 2 {
+		t.Errorf("warning fired %d times across 2 runs, want 2", fired)
+	}
+}
@@ -0,0 +1,50 @@
+package cua
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+)
+
+func TestForwardEventsSequence(t *testing.T) {
+	in := make(chan interfaces.AgentStreamEvent, 10)
+	in <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventThinking, Content: "plan"}
+	in <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventToolCall, ToolCall: &interfaces.ToolCallEvent{ID: "1", Name: "mouse_click", Arguments: "{}"}}
+	in <- interfaces.AgentStreamEvent{Type: "heartbeat"} // Unknown events are skipped without a sequence number
+	in <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventToolResult, Content: `{"success":true}`}
+	in <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventContent, Content: "done"}
+	in <- interfaces.AgentStreamEvent{Type: interfaces.AgentEventComplete, Content: "done"}
+	close(in)
+
+	out := make(chan RunEvent, 10)
+	forwardEvents(context.Background(), in, out)
+	close(out)
+
+	var events []RunEvent
+	for event := range out {
+		events = append(events, event)
+	}
+
+	wantTypes := []EventType{EventThinking, EventToolCall, EventToolResult, EventContent, EventComplete}
+	if len(events) != len(wantTypes) {
+		t.Fatalf("got %d events, want %d", len(events), len(wantTypes))
+	}
+	for i, event := range events {
+		if event.Type != wantTypes[i] {
+			t.Errorf("event %d type = %v, want %v", i, event.Type, wantTypes[i])
+		}
+		if event.Seq != i+1 {
+			t.Errorf("event %d Seq = %d, want %d", i, event.Seq, i+1)
+		}
+		if event.Timestamp.IsZero() {
+			t.Errorf("event %d has no timestamp", i)
+		}
+		if i > 0 && event.Timestamp.Before(events[i-1].Timestamp) {
+			t.Errorf("event %d timestamp %v is before the previous event's %v", i, event.Timestamp, events[i-1].Timestamp)
+		}
+	}
+	if call := events[1].ToolCall; call == nil || call.Name != "mouse_click" {
+		t.Errorf("tool call = %+v, want mouse_click", call)
+	}
+}
@@ -0,0 +1,48 @@
+package cua
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// templatePlaceholder matches {param} placeholders in task templates.
+var templatePlaceholder = regexp.MustCompile(`\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// RenderTemplate returns the task registered under name with its {param}
+// placeholders replaced by params. Every placeholder must be supplied.
+func (c *CUA) RenderTemplate(name string, params map[string]string) (string, error) {
+	tmpl, ok := c.config.TaskTemplates[name]
+	if !ok {
+		return "", fmt.Errorf("unknown task template: %s", name)
+	}
+
+	var missing []string
+	seen := make(map[string]bool)
+	for _, m := range templatePlaceholder.FindAllStringSubmatch(tmpl, -1) {
+		param := m[1]
+		if _, ok := params[param]; !ok && !seen[param] {
+			missing = append(missing, param)
+		}
+		seen[param] = true
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("task template %s is missing parameters: %s", name, strings.Join(missing, ", "))
+	}
+
+	return templatePlaceholder.ReplaceAllStringFunc(tmpl, func(placeholder string) string {
+		return params[placeholder[1:len(placeholder)-1]]
+	}), nil
+}
+
+// RunTemplate renders the task template registered with WithTaskTemplate and runs it.
+func (c *CUA) RunTemplate(ctx context.Context, name string, params map[string]string) (string, error) {
+	task, err := c.RenderTemplate(name, params)
+	if err != nil {
+		return "", err
+	}
+	return c.Run(ctx, task)
+}
@@ -0,0 +1,64 @@
+package cua
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	cfg := defaultConfig()
+	WithTaskTemplate("search", "Search {site} for {query}")(cfg)
+	WithTaskTemplate("repeat", "Open {app}, then close {app}")(cfg)
+	WithTaskTemplate("static", "Take a screenshot")(cfg)
+	WithTaskTemplate("braces", "Type {not a param} and {x}")(cfg)
+	WithTaskTemplate("replaced", "old")(cfg)
+	WithTaskTemplate("replaced", "new {v}")(cfg)
+	c := newTestCUA(cfg)
+
+	tests := []struct {
+		name    string
+		tmpl    string
+		params  map[string]string
+		want    string
+		wantErr string
+	}{
+		{"substitutes", "search", map[string]string{"site": "example.com", "query": "go"}, "Search example.com for go", ""},
+		{"repeated placeholder", "repeat", map[string]string{"app": "Notes"}, "Open Notes, then close Notes", ""},
+		{"extra params ignored", "search", map[string]string{"site": "a", "query": "b", "unused": "c"}, "Search a for b", ""},
+		{"empty value allowed", "search", map[string]string{"site": "a", "query": ""}, "Search a for ", ""},
+		{"no placeholders", "static", nil, "Take a screenshot", ""},
+		{"values not re-expanded", "search", map[string]string{"site": "{query}", "query": "x"}, "Search {query} for x", ""},
+		{"only identifiers are placeholders", "braces", map[string]string{"x": "1"}, "Type {not a param} and 1", ""},
+		{"re-registered", "replaced", map[string]string{"v": "1"}, "new 1", ""},
+		{"missing one", "search", map[string]string{"site": "a"}, "", "task template search is missing parameters: query"},
+		{"missing all sorted", "search", nil, "", "missing parameters: query, site"},
+		{"missing repeated once", "repeat", nil, "", "missing parameters: app"},
+		{"unknown template", "nope", nil, "", "unknown task template: nope"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.RenderTemplate(tt.tmpl, tt.params)
+			if tt.wantErr != "" {
+				if err == nil || !strings.HasSuffix(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want it to end with %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunTemplateRejectsBeforeRunning(t *testing.T) {
+	// No agent is configured, so reaching Run would panic
+	c := newTestCUA(defaultConfig())
+	if _, err := c.RunTemplate(context.Background(), "missing", nil); err == nil {
+		t.Error("expected an error for an unknown template")
+	}
+}
@@ -1,7 +1,16 @@
 // Package cua provides a cross-platform Computer Use Agent for AI-powered desktop automation.
 package cua
 
-import "sync"
+import (
+	"image"
+	"sync"
+	"time"
+
+	"github.com/Ingenimax/agent-sdk-go/pkg/interfaces"
+
+	"github.com/anxuanzi/cua/internal/safety"
+	"github.com/anxuanzi/cua/pkg/screen"
+)
 
 // LLMProvider represents the LLM provider to use.
 type LLMProvider string
@@ -13,8 +22,23 @@ const (
 	ProviderOpenAI LLMProvider = "openai"
 	// ProviderGemini uses Google's Gemini models.
 	ProviderGemini LLMProvider = "gemini"
+	// ProviderLocal uses a local OpenAI-compatible server (Ollama, LM Studio, vLLM).
+	// Requires WithModel; the API key is optional.
+	ProviderLocal LLMProvider = "local"
 )
 
+// DefaultLocalBaseURL is the endpoint used by ProviderLocal when no base URL is set.
+// It points at Ollama's OpenAI-compatible API.
+const DefaultLocalBaseURL = "http://localhost:11434/v1"
+
+// defaultModels maps each hosted provider to the model used when WithModel is not set.
+// ProviderLocal has no default and requires WithModel.
+var defaultModels = map[LLMProvider]string{
+	ProviderAnthropic: "claude-sonnet-4-20250514",
+	ProviderOpenAI:    "gpt-4o",
+	ProviderGemini:    "gemini-2.5-flash",
+}
+
 // TokenUsage represents token usage statistics.
 type TokenUsage struct {
 	// InputTokens is the number of input/prompt tokens used.
@@ -52,15 +76,30 @@ func (s *UsageStats) Add(usage *TokenUsage, llmCalls, toolCalls int, timeMs int6
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	s.addLocked(usage, llmCalls, timeMs)
+	s.TotalRuns++
+	s.TotalToolCalls += toolCalls
+}
+
+// addCalls adds the usage of LLM calls made outside a run, such as planning.
+func (s *UsageStats) addCalls(usage *TokenUsage, llmCalls int, timeMs int64) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.addLocked(usage, llmCalls, timeMs)
+}
+
+// addLocked adds token usage, LLM calls, and time. s.mu must be held.
+func (s *UsageStats) addLocked(usage *TokenUsage, llmCalls int, timeMs int64) {
 	if usage != nil {
 		s.TotalInputTokens += usage.InputTokens
 		s.TotalOutputTokens += usage.OutputTokens
 		s.TotalTokens += usage.TotalTokens
 		s.TotalReasoningTokens += usage.ReasoningTokens
 	}
-	s.TotalRuns++
 	s.TotalLLMCalls += llmCalls
-	s.TotalToolCalls += toolCalls
 	s.TotalTimeMs += timeMs
 }
 
@@ -100,9 +139,38 @@ func (s *UsageStats) Reset() {
 	s.TotalTimeMs = 0
 }
 
+// CaptureMeta describes a screenshot passed to a ScreenshotHook.
+type CaptureMeta = screen.CaptureMeta
+
+// ScreenshotHook post-processes every screenshot after resizing and before encoding.
+// It may modify img in place or return a replacement; returning nil keeps img.
+// Hooks run on the tool-execution path and should be fast.
+type ScreenshotHook = screen.CaptureHook
+
+// OperatingHours is a daily local-time window during which actions are allowed.
+type OperatingHours = safety.OperatingHours
+
+// ScreenshotScaler selects the interpolation used to downscale screenshots.
+type ScreenshotScaler = screen.Scaler
+
+// Screenshot scalers, from fastest to sharpest.
+const (
+	ScalerNearestNeighbor = screen.ScalerNearestNeighbor
+	ScalerBiLinear        = screen.ScalerBiLinear
+	ScalerCatmullRom      = screen.ScalerCatmullRom
+)
+
+// Tracer is an alias to the agent-sdk-go Tracer interface.
+// The agent-sdk-go tracing package provides OpenTelemetry
+// (tracing.NewOTelTracerWrapper) and Langfuse implementations.
+type Tracer = interfaces.Tracer
+
 // TokenLimitCallback is called when token usage approaches or exceeds limits.
 type TokenLimitCallback func(current, limit int, percentUsed float64)
 
+// ToolCallBudgetCallback is called when a run's executed tool calls approach its tool call budget.
+type ToolCallBudgetCallback func(used, limit int, percentUsed float64)
+
 // Config holds the configuration for the CUA agent.
 type Config struct {
 	// Provider specifies which LLM provider to use.
@@ -114,15 +182,23 @@ type Config struct {
 	// Model overrides the default model for the provider.
 	Model string
 
+	// FallbackModels are models of the same provider to retry failed runs on, in order.
+	FallbackModels []string
+
 	// BaseURL is the custom API endpoint URL (optional).
 	// For Gemini: overrides the default https://generativelanguage.googleapis.com/
 	// For OpenAI: overrides the default https://api.openai.com/v1
 	// For Anthropic: overrides the default https://api.anthropic.com
+	// For Local: overrides the default http://localhost:11434/v1 (Ollama)
 	BaseURL string
 
 	// ScreenIndex specifies which screen to use for multi-monitor setups.
 	ScreenIndex int
 
+	// Vision indicates whether the model can interpret screenshots (default: true).
+	// When false, the system prompt switches to a keyboard-first, text-only workflow.
+	Vision bool
+
 	// EnableReasoning enables extended thinking/reasoning mode.
 	EnableReasoning bool
 
@@ -151,6 +227,118 @@ type Config struct {
 
 	// OnTokenLimitWarning is called when token usage approaches the limit.
 	OnTokenLimitWarning TokenLimitCallback
+
+	// ToolCallBudget is the expected maximum number of tool calls per run, used
+	// for OnToolCallBudgetWarning. It is not enforced. 0 disables the warning.
+	ToolCallBudget int
+
+	// ToolCallBudgetWarningThreshold is the percentage (0-100) of ToolCallBudget at
+	// which to trigger OnToolCallBudgetWarning. Default is 80.
+	ToolCallBudgetWarningThreshold int
+
+	// OnToolCallBudgetWarning is called once per run when its tool calls reach the threshold.
+	OnToolCallBudgetWarning ToolCallBudgetCallback
+
+	// MaxActions is the maximum number of tool calls per run; the run stops
+	// with ErrMaxActions when it tries another. 0 (the default) is unlimited.
+	MaxActions int
+
+	// HelpRequests offers the request_help tool, which stops a run with a
+	// *NeedsHelpError (default: false).
+	HelpRequests bool
+
+	// ScreenshotHook post-processes each screenshot before it is encoded.
+	ScreenshotHook ScreenshotHook
+
+	// ScreenshotScaler selects the screenshot downscaling interpolation (default: bilinear).
+	ScreenshotScaler ScreenshotScaler
+
+	// ShowCursor draws the mouse pointer onto screenshots (default: false).
+	ShowCursor bool
+
+	// ScreenshotGrayscale converts screenshots to grayscale before encoding (default: false).
+	ScreenshotGrayscale bool
+
+	// ScreenshotPalette, if > 0, quantizes screenshots to at most this many colors (default: 0, off).
+	ScreenshotPalette int
+
+	// ScreenshotJPEGQuality is the JPEG quality (1-100) of screenshots sent to the model (default: 65).
+	ScreenshotJPEGQuality int
+
+	// ScreenshotDedup skips resending a screenshot identical to the previous one in a run (default: false).
+	ScreenshotDedup bool
+
+	// ScreenshotThrottle is the minimum interval between screen captures (default: 0, no limit).
+	ScreenshotThrottle time.Duration
+
+	// DefaultCaptureRegion, if set, crops screenshots to this region in 0-1000 normalized coordinates.
+	DefaultCaptureRegion *image.Rectangle
+
+	// CoordSnap is the grid, in pixels, that pointer coordinates snap to (default: 0, no snapping).
+	CoordSnap int
+
+	// ToolResultMaxChars caps the size of tool results fed back to the model (0 = no limit).
+	ToolResultMaxChars int
+
+	// OperatingHours, if set, restricts actions to a daily local-time window.
+	OperatingHours *OperatingHours
+
+	// BlockOnLockScreen blocks actions while the screen looks locked (default: false).
+	BlockOnLockScreen bool
+
+	// ActionRateLimit is the default maximum number of calls per minute for each action tool (default: 0, unlimited).
+	ActionRateLimit int
+
+	// ActionRateLimits overrides ActionRateLimit per tool name; 0 means unlimited.
+	ActionRateLimits map[string]int
+
+	// TaskTemplates maps template names to parameterized tasks (see RunTemplate).
+	TaskTemplates map[string]string
+
+	// PlanApproval, if set, must approve the model's plan before each run executes.
+	PlanApproval PlanApprovalFunc
+
+	// ActionFilter is called before each tool execution to allow, deny, or rewrite it.
+	ActionFilter ActionFilter
+
+	// ToolConfirmation, if set, is asked to approve each tool call after ActionFilter.
+	ToolConfirmation ToolConfirmFunc
+
+	// KeyboardLayout is the active keyboard layout, e.g. "us", "de", "fr" (default: "us").
+	KeyboardLayout string
+
+	// TypeFileRoot is the directory keyboard_type may read files from via
+	// file_path. Empty (the default) disables typing files.
+	TypeFileRoot string
+
+	// PasteThreshold is the text length above which keyboard_type pastes
+	// instead of typing. Zero (the default) never pastes text.
+	PasteThreshold int
+
+	// BlockedKeyCombos lists key combinations keyboard_press refuses to press.
+	BlockedKeyCombos []string
+
+	// ConfirmKeyCombo, if set, can approve a blocked key combination.
+	ConfirmKeyCombo func(combo string) bool
+
+	// Tracer receives spans for each run, LLM call, and tool call (optional).
+	Tracer Tracer
+
+	// ControlServerAddr is the listen address of the HTTP control server (optional).
+	ControlServerAddr string
+
+	// ControlServerToken is the bearer token required by the control server.
+	ControlServerToken string
+
+	// CaptureOnFailure attaches a screenshot to the Result of failed runs (default: false).
+	CaptureOnFailure bool
+
+	// ResultStore, if set, persists the result of each run (optional).
+	ResultStore ResultStore
+
+	// Queueing serializes concurrent runs in submission order (default: false).
+	// When disabled, concurrent runs execute in parallel against the same desktop.
+	Queueing bool
 }
 
 // defaultConfig returns the default configuration.
@@ -159,6 +347,7 @@ func defaultConfig() *Config {
 		Provider:        ProviderAnthropic,
 		Model:           "",
 		ScreenIndex:     0,
+		Vision:          true,
 		EnableReasoning: true,
 		ReasoningBudget: 4096,
 		MaxIterations:   50,